@@ -0,0 +1,403 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func handleToBrunoCommand() {
+	fs := flag.NewFlagSet("to-bruno", flag.ExitOnError)
+	args := parseArgs(fs, os.Args[2:])
+
+	if len(args) < 2 {
+		fmt.Println("Usage: postmanzier to-bruno <input-collection> <output-dir> [<postman-environment> ...]")
+		fmt.Println("Example: postmanzier to-bruno collection.json bruno/")
+		os.Exit(1)
+	}
+
+	inputFile := args[0]
+	collection, err := loadCollection(inputFile)
+	if err != nil {
+		log.Fatalf("Error reading input collection: %v", err)
+	}
+
+	// Environments of an HTTPie (or Insomnia) input come first, followed by
+	// any Postman environment files given
+	var environments []PostmanEnvironment
+	data, err := readInput(inputFile)
+	if err != nil {
+		log.Fatalf("Error reading input file: %v", err)
+	}
+	if isWorkspaceFormat(detectInputFormat(data)) {
+		if httpieWorkspace, err := parseWorkspace(data, inputFile); err == nil {
+			for _, env := range httpieWorkspace.Environments {
+				environments = append(environments, convertEnvironment(env))
+			}
+		}
+	}
+	for _, envFile := range args[2:] {
+		envData, err := os.ReadFile(envFile)
+		if err != nil {
+			log.Fatalf("Error reading environment file %s: %v", envFile, err)
+		}
+		var env PostmanEnvironment
+		if err := json.Unmarshal(envData, &env); err != nil {
+			log.Fatalf("Error parsing Postman environment %s: %v", envFile, err)
+		}
+		environments = append(environments, env)
+	}
+
+	outputDir := generateUniqueFilename(args[1])
+	files := renderBrunoCollection(collection, environments)
+	for _, file := range files {
+		path := filepath.Join(outputDir, file.path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			log.Fatalf("Error creating output directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(file.content), 0644); err != nil {
+			log.Fatalf("Error writing output file: %v", err)
+		}
+	}
+
+	fmt.Println("Bruno export completed!")
+	fmt.Printf("* Total APIs: %d\n", countPostmanRequests(collection.Item))
+	fmt.Printf("* Total environments: %d\n", len(environments))
+	fmt.Printf("* Total files: %d\n", len(files))
+	fmt.Printf("--> Output directory: %s\n", outputDir)
+}
+
+// brunoFile is a file of a Bruno collection, relative to its root.
+type brunoFile struct {
+	path    string
+	content string
+}
+
+// renderBrunoCollection renders a collection as Bruno files: bruno.json and
+// collection.bru at the root, one .bru file per request, one directory
+// (with a folder.bru) per folder and environments/<name>.bru.
+func renderBrunoCollection(collection PostmanCollection, environments []PostmanEnvironment) []brunoFile {
+	config, _ := json.MarshalIndent(map[string]any{
+		"version": "1",
+		"name":    collection.Info.Name,
+		"type":    "collection",
+		"ignore":  []string{"node_modules", ".git"},
+	}, "", "  ")
+
+	files := []brunoFile{{path: "bruno.json", content: string(config) + "\n"}}
+
+	var root brunoWriter
+	root.authBlocks("auth", collection.Auth)
+	if len(collection.Variable) > 0 {
+		root.dictionary("vars:pre-request", func(add func(key, value string, enabled bool)) {
+			for _, v := range collection.Variable {
+				add(v.Key, v.Value, true)
+			}
+		})
+	}
+	if root.Len() > 0 {
+		files = append(files, brunoFile{path: "collection.bru", content: root.String()})
+	}
+
+	files = append(files, renderBrunoItems(collection.Item, "")...)
+
+	usedNames := map[string]bool{}
+	for _, env := range environments {
+		var w brunoWriter
+		var secrets []string
+		w.dictionary("vars", func(add func(key, value string, enabled bool)) {
+			for _, v := range env.Values {
+				if v.Type == "secret" {
+					// Bruno keeps secret values out of the collection files
+					secrets = append(secrets, v.Key)
+					continue
+				}
+				add(v.Key, v.Value, v.Enabled)
+			}
+		})
+		if len(secrets) > 0 {
+			w.block("vars:secret [", "]", strings.Join(secrets, ",\n"))
+		}
+		files = append(files, brunoFile{
+			path:    filepath.Join("environments", uniqueBrunoName(env.Name, "environment", usedNames)+".bru"),
+			content: w.String(),
+		})
+	}
+
+	return files
+}
+
+// renderBrunoItems renders the requests and folders of one directory.
+// Bruno orders them by their meta seq.
+func renderBrunoItems(items []PostmanItem, dir string) []brunoFile {
+	var files []brunoFile
+	// Keep request and folder names clear of the files Bruno reserves
+	usedNames := map[string]bool{"folder": true, "collection": true, "environments": true}
+
+	for i, item := range items {
+		name := uniqueBrunoName(item.Name, "request", usedNames)
+		seq := i + 1
+
+		if item.Request == nil {
+			folderDir := filepath.Join(dir, name)
+			var w brunoWriter
+			w.dictionary("meta", func(add func(key, value string, enabled bool)) {
+				add("name", item.Name, true)
+				add("seq", fmt.Sprint(seq), true)
+			})
+			w.authBlocks("auth", item.Auth)
+			files = append(files, brunoFile{path: filepath.Join(folderDir, "folder.bru"), content: w.String()})
+			files = append(files, renderBrunoItems(item.Item, folderDir)...)
+			continue
+		}
+
+		files = append(files, brunoFile{
+			path:    filepath.Join(dir, name+".bru"),
+			content: renderBrunoRequest(item, seq),
+		})
+	}
+	return files
+}
+
+// renderBrunoRequest renders one request as a .bru file.
+func renderBrunoRequest(item PostmanItem, seq int) string {
+	req := item.Request
+	method := strings.ToLower(req.Method)
+	if method == "" {
+		method = "get"
+	}
+
+	url := req.URL.Raw
+	if url == "" {
+		url = postmanURLString(req.URL)
+	}
+
+	bodyMode, bodyBlock, bodyContent := brunoBody(req.Body, req.Header)
+
+	requestType := "http"
+	if bodyMode == "graphql" {
+		requestType = "graphql"
+	}
+
+	var w brunoWriter
+	w.dictionary("meta", func(add func(key, value string, enabled bool)) {
+		add("name", item.Name, true)
+		add("type", requestType, true)
+		add("seq", fmt.Sprint(seq), true)
+	})
+	w.dictionary(method, func(add func(key, value string, enabled bool)) {
+		add("url", url, true)
+		add("body", bodyMode, true)
+		add("auth", brunoAuthMode(req.Auth), true)
+	})
+
+	if len(req.URL.Query) > 0 {
+		w.dictionary("params:query", func(add func(key, value string, enabled bool)) {
+			for _, param := range req.URL.Query {
+				add(param.Key, param.Value, !param.Disabled)
+			}
+		})
+	}
+	if len(req.URL.Variable) > 0 {
+		w.dictionary("params:path", func(add func(key, value string, enabled bool)) {
+			for _, variable := range req.URL.Variable {
+				add(variable.Key, variable.Value, true)
+			}
+		})
+	}
+	var headers []PostmanHeader
+	for _, header := range req.Header {
+		// Bruno sets the multipart boundary itself
+		if bodyMode == "multipartForm" && strings.EqualFold(header.Key, "Content-Type") && !strings.Contains(header.Value, "boundary=") {
+			continue
+		}
+		headers = append(headers, header)
+	}
+	if len(headers) > 0 {
+		w.dictionary("headers", func(add func(key, value string, enabled bool)) {
+			for _, header := range headers {
+				add(header.Key, header.Value, !header.Disabled)
+			}
+		})
+	}
+
+	if req.Auth != nil && req.Auth.Type != "noauth" {
+		w.authBlocks("", req.Auth)
+	}
+
+	for i, block := range bodyBlock {
+		w.block(block+" {", "}", bodyContent[i])
+	}
+
+	return w.String()
+}
+
+// brunoBody returns the Bruno body mode and the body blocks with their
+// contents. Raw bodies use the json, xml or text block depending on the
+// language or Content-Type.
+func brunoBody(body *PostmanBody, headers []PostmanHeader) (string, []string, []string) {
+	if body == nil {
+		return "none", nil, nil
+	}
+
+	switch body.Mode {
+	case "raw":
+		if body.Raw == "" {
+			return "none", nil, nil
+		}
+		contentType := ""
+		for _, header := range headers {
+			if strings.EqualFold(header.Key, "Content-Type") {
+				contentType = strings.ToLower(header.Value)
+			}
+		}
+		switch {
+		case (body.Options != nil && body.Options.Raw.Language == "json") || jsonContentTypeRegex.MatchString(contentType):
+			return "json", []string{"body:json"}, []string{body.Raw}
+		case (body.Options != nil && body.Options.Raw.Language == "xml") || strings.Contains(contentType, "xml"):
+			return "xml", []string{"body:xml"}, []string{body.Raw}
+		}
+		return "text", []string{"body:text"}, []string{body.Raw}
+	case "urlencoded":
+		var lines []string
+		for _, param := range body.URLEncoded {
+			lines = append(lines, brunoPair(param.Key, param.Value, !param.Disabled))
+		}
+		return "formUrlEncoded", []string{"body:form-urlencoded"}, []string{strings.Join(lines, "\n")}
+	case "formdata":
+		var lines []string
+		for _, param := range body.FormData {
+			value := param.Value
+			if param.Type == "file" {
+				value = "@file(" + param.Src + ")"
+			}
+			lines = append(lines, brunoPair(param.Key, value, !param.Disabled))
+		}
+		return "multipartForm", []string{"body:multipart-form"}, []string{strings.Join(lines, "\n")}
+	case "graphql":
+		if body.GraphQL == nil {
+			return "none", nil, nil
+		}
+		blocks, contents := []string{"body:graphql"}, []string{body.GraphQL.Query}
+		if strings.TrimSpace(body.GraphQL.Variables) != "" {
+			blocks = append(blocks, "body:graphql:vars")
+			contents = append(contents, body.GraphQL.Variables)
+		}
+		return "graphql", blocks, contents
+	case "file":
+		if body.File == nil || body.File.Src == "" {
+			return "none", nil, nil
+		}
+		return "file", []string{"body:file"}, []string{"file: @file(" + body.File.Src + ")"}
+	}
+	return "none", nil, nil
+}
+
+// brunoAuthMode maps a request's auth to a Bruno auth mode. Requests
+// without their own auth inherit it from their folder or collection.
+func brunoAuthMode(auth *PostmanAuth) string {
+	if auth == nil {
+		return "inherit"
+	}
+	switch auth.Type {
+	case "basic", "bearer", "apikey":
+		return auth.Type
+	}
+	return "none"
+}
+
+// brunoWriter builds the blocks of a .bru file.
+type brunoWriter struct {
+	strings.Builder
+}
+
+// block writes name, the content indented by two spaces, and end.
+func (w *brunoWriter) block(start, end, content string) {
+	if w.Len() > 0 {
+		w.WriteString("\n")
+	}
+	w.WriteString(start + "\n")
+	for _, line := range strings.Split(content, "\n") {
+		if line == "" {
+			w.WriteString("\n")
+		} else {
+			w.WriteString("  " + line + "\n")
+		}
+	}
+	w.WriteString(end + "\n")
+}
+
+// dictionary writes a block of key: value lines. Disabled entries are
+// prefixed with ~.
+func (w *brunoWriter) dictionary(name string, fill func(add func(key, value string, enabled bool))) {
+	var lines []string
+	fill(func(key, value string, enabled bool) {
+		lines = append(lines, brunoPair(key, value, enabled))
+	})
+	w.block(name+" {", "}", strings.Join(lines, "\n"))
+}
+
+// authBlocks writes the auth of a collection or folder (with a mode block
+// named by modeBlock) or of a request (modeBlock empty, as the mode is in
+// the method block).
+func (w *brunoWriter) authBlocks(modeBlock string, auth *PostmanAuth) {
+	if auth == nil {
+		return
+	}
+	mode := brunoAuthMode(auth)
+	if modeBlock != "" {
+		w.dictionary(modeBlock, func(add func(key, value string, enabled bool)) {
+			add("mode", mode, true)
+		})
+	}
+
+	switch mode {
+	case "basic":
+		w.dictionary("auth:basic", func(add func(key, value string, enabled bool)) {
+			add("username", postmanAuthValue(auth.Basic, "username"), true)
+			add("password", postmanAuthValue(auth.Basic, "password"), true)
+		})
+	case "bearer":
+		w.dictionary("auth:bearer", func(add func(key, value string, enabled bool)) {
+			add("token", postmanAuthValue(auth.Bearer, "token"), true)
+		})
+	case "apikey":
+		placement := "header"
+		if postmanAuthValue(auth.APIKey, "in") == "query" {
+			placement = "queryparams"
+		}
+		w.dictionary("auth:apikey", func(add func(key, value string, enabled bool)) {
+			add("key", postmanAuthValue(auth.APIKey, "key"), true)
+			add("value", postmanAuthValue(auth.APIKey, "value"), true)
+			add("placement", placement, true)
+		})
+	}
+}
+
+// brunoPair renders a dictionary entry. Bruno values are single-line, so
+// line breaks are escaped.
+func brunoPair(key, value string, enabled bool) string {
+	line := key + ": " + strings.NewReplacer("\r", `\r`, "\n", `\n`).Replace(value)
+	if !enabled {
+		line = "~" + line
+	}
+	return line
+}
+
+// uniqueBrunoName returns a file name for name that is not yet in used.
+func uniqueBrunoName(name, fallback string, used map[string]bool) string {
+	base := sanitizeFilename(name)
+	if base == "" {
+		base = fallback
+	}
+	unique := base
+	for i := 1; used[strings.ToLower(unique)]; i++ {
+		unique = fmt.Sprintf("%s_%d", base, i)
+	}
+	used[strings.ToLower(unique)] = true
+	return unique
+}
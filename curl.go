@@ -0,0 +1,202 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func handleToCurlCommand() {
+	fs := flag.NewFlagSet("to-curl", flag.ExitOnError)
+	var resolve, split bool
+	var env string
+	registerVariableFlags(fs, &resolve, &env)
+	fs.BoolVar(&split, "split", false, "treat the output as a directory and write one script per folder")
+	args := parseArgs(fs, os.Args[2:])
+
+	if len(args) < 2 {
+		fmt.Println("Usage: postmanzier to-curl [-resolve] [-env <environment>] [-split] <input-collection> <output-script-or-dir>")
+		fmt.Println("Example: postmanzier to-curl -env Production collection.json requests.sh")
+		os.Exit(1)
+	}
+
+	inputFile := args[0]
+	outputPath := args[1]
+
+	collection, err := loadCollection(inputFile)
+	if err != nil {
+		log.Fatalf("Error reading input collection: %v", err)
+	}
+
+	var values map[string]string
+	if resolve || env != "" {
+		if values, err = loadVariables(collection, inputFile, env); err != nil {
+			log.Fatalf("Error loading variables: %v", err)
+		}
+	}
+
+	scripts := renderCurlScripts(collection, values, split)
+
+	var paths []string
+	if split {
+		if err := os.MkdirAll(outputPath, 0755); err != nil {
+			log.Fatalf("Error creating output directory: %v", err)
+		}
+		for _, script := range scripts {
+			path := generateUniqueFilename(filepath.Join(outputPath, script.name+".sh"))
+			if err := os.WriteFile(path, []byte(script.content), 0755); err != nil {
+				log.Fatalf("Error writing output file: %v", err)
+			}
+			paths = append(paths, path)
+		}
+	} else {
+		path := generateUniqueFilename(outputPath)
+		if err := os.WriteFile(path, []byte(scripts[0].content), 0755); err != nil {
+			log.Fatalf("Error writing output file: %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	fmt.Println("curl export completed!")
+	fmt.Printf("* Total APIs: %d\n", countPostmanRequests(collection.Item))
+	for _, path := range paths {
+		fmt.Printf("--> Output file: %s\n", path)
+	}
+}
+
+// shellScript is a generated script and the base name it should be saved as.
+type shellScript struct {
+	name    string
+	content string
+}
+
+// renderCurlScripts renders every request as a curl command. With split,
+// requests are grouped into one script per folder; otherwise a single
+// script is returned.
+func renderCurlScripts(collection PostmanCollection, values map[string]string, split bool) []shellScript {
+	var scripts []shellScript
+	index := map[string]int{}
+
+	walkRequests(collection, func(item PostmanItem, folders []string, auth *PostmanAuth) {
+		name, header := collection.Info.Name, collection.Info.Name
+		if split {
+			name = folderFileName(collection.Info.Name, folders)
+			header = strings.Join(append([]string{collection.Info.Name}, folders...), " / ")
+		}
+
+		i, ok := index[name]
+		if !ok {
+			i = len(scripts)
+			index[name] = i
+			scripts = append(scripts, shellScript{
+				name:    name,
+				content: "#!/bin/sh\n# " + shellComment(header) + "\n",
+			})
+		}
+
+		title := item.Name
+		if !split && len(folders) > 0 {
+			title = strings.Join(folders, " / ") + " / " + item.Name
+		}
+		scripts[i].content += "\n# " + shellComment(title) + "\n" + curlCommand(item.Request, auth, values) + "\n"
+	})
+
+	if len(scripts) == 0 {
+		scripts = append(scripts, shellScript{name: collection.Info.Name, content: "#!/bin/sh\n"})
+	}
+	return scripts
+}
+
+// curlCommand renders a request as a multi-line curl command.
+func curlCommand(req *PostmanRequest, auth *PostmanAuth, values map[string]string) string {
+	resolve := func(s string) string {
+		return resolveVariables(s, values)
+	}
+
+	method := strings.ToUpper(req.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	var headers []string
+	for _, header := range req.Header {
+		if header.Disabled {
+			continue
+		}
+		headers = append(headers, "-H "+shellQuote(resolve(header.Key+": "+header.Value)))
+	}
+
+	if key, value := authHeader(auth); key != "" {
+		headers = append(headers, "-H "+shellQuote(resolve(key+": "+value)))
+	} else if auth != nil && auth.Type == "basic" {
+		headers = append(headers, "-u "+shellQuote(resolve(postmanAuthValue(auth.Basic, "username")+":"+postmanAuthValue(auth.Basic, "password"))))
+	}
+
+	var data []string
+	if body := req.Body; body != nil {
+		switch body.Mode {
+		case "raw":
+			if body.Raw != "" {
+				data = append(data, "--data-raw "+shellQuote(resolve(body.Raw)))
+			}
+		case "urlencoded":
+			for _, param := range body.URLEncoded {
+				if !param.Disabled {
+					data = append(data, "--data-urlencode "+shellQuote(resolve(param.Key+"="+param.Value)))
+				}
+			}
+		case "formdata":
+			for _, param := range body.FormData {
+				if param.Disabled {
+					continue
+				}
+				// -F would read a value starting with @ or < from a file and
+				// parse ;type= in it; --form-string sends text as is
+				if param.Type == "file" {
+					data = append(data, "-F "+shellQuote(resolve(param.Key+"=@"+param.Src)))
+				} else {
+					data = append(data, "--form-string "+shellQuote(resolve(param.Key+"="+param.Value)))
+				}
+			}
+		case "graphql":
+			if body.GraphQL != nil {
+				if postmanHeaderValue(req.Header, "Content-Type") == "" {
+					headers = append(headers, "-H "+shellQuote("Content-Type: application/json"))
+				}
+				data = append(data, "--data-raw "+shellQuote(resolve(graphQLPayload(body.GraphQL))))
+			}
+		case "file":
+			if body.File != nil && body.File.Src != "" {
+				data = append(data, "--data-binary "+shellQuote("@"+resolve(body.File.Src)))
+			}
+		}
+	}
+
+	// curl switches to POST when it sends data, so GET must be explicit
+	// then; -X HEAD would make it wait for a body that never comes
+	args := []string{"curl"}
+	switch {
+	case method == "HEAD":
+		args = append(args, "-I")
+	case method != "GET" || len(data) > 0:
+		args = append(args, "-X "+method)
+	}
+	args = append(args, shellQuote(resolve(requestURL(req, auth))))
+	args = append(append(args, headers...), data...)
+
+	return strings.Join(args, " \\\n  ")
+}
+
+// shellQuote quotes s for POSIX shells. Single quotes keep everything
+// literal, including newlines; embedded single quotes become '\''.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// shellComment flattens text onto one line for use in a # comment.
+func shellComment(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,478 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var curlCommandRegex = regexp.MustCompile(`(?m)^\s*curl\s`)
+
+// isCurlScript reports whether data looks like one or more curl commands
+// (a shell script or pasted commands) rather than a JSON/YAML document.
+func isCurlScript(data []byte) bool {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return false
+	}
+	return curlCommandRegex.MatchString(trimmed)
+}
+
+// curlShortFlagsWithValue lists the single-letter curl options that take an
+// argument, so "-XPOST" and "-sX POST" are split correctly.
+const curlShortFlagsWithValue = "XHdFuAbeoTmxwrEKCYyzQcDP"
+
+// curlLongFlagsWithValue lists long options that take an argument but are
+// otherwise ignored.
+var curlLongFlagsWithValue = map[string]bool{
+	"--output": true, "--max-time": true, "--connect-timeout": true, "--proxy": true,
+	"--retry": true, "--write-out": true, "--cacert": true, "--cert": true, "--key": true,
+	"--resolve": true, "--limit-rate": true, "--interface": true, "--cookie-jar": true,
+	"--retry-delay": true, "--retry-max-time": true, "--config": true, "--dump-header": true,
+	"--proxy-user": true, "--range": true, "--time-cond": true, "--oauth2-bearer": true,
+	"--user-agent": true, "--referer": true, "--cookie": true, "--upload-file": true,
+}
+
+// convertCurlToWorkspace parses curl commands into an HTTPie workspace, so
+// they go through the same conversion path as HTTPie requests. A comment
+// line directly above a command is used as the request name.
+func convertCurlToWorkspace(data []byte, sourcePath string) HTTPieWorkspace {
+	name := strings.TrimSuffix(filepath.Base(sourcePath), filepath.Ext(sourcePath))
+	if sourcePath == "-" || name == "" {
+		name = "curl commands"
+	}
+
+	workspace := HTTPieWorkspace{
+		Entry: HTTPieEntry{
+			Name: name,
+			Auth: HTTPieAuth{Type: "none"},
+		},
+	}
+
+	for _, command := range splitShellCommands(string(data)) {
+		if len(command.args) == 0 || filepath.Base(command.args[0]) != "curl" {
+			continue
+		}
+		req, err := parseCurlCommand(command.args[1:])
+		if err != nil {
+			log.Printf("Warning: skipping curl command: %v", err)
+			continue
+		}
+		if command.comment != "" {
+			req.Name = command.comment
+		}
+		workspace.Entry.Requests = append(workspace.Entry.Requests, req)
+	}
+
+	return workspace
+}
+
+// parseCurlCommand builds an HTTPie request from curl arguments.
+func parseCurlCommand(args []string) (HTTPieRequest, error) {
+	req := HTTPieRequest{
+		Headers:     []HTTPieHeader{},
+		QueryParams: []HTTPieQueryParam{},
+		PathParams:  []HTTPiePathParam{},
+		Auth:        HTTPieAuth{Type: "none"},
+		Body:        HTTPieBody{Type: "none", Form: HTTPieForm{Fields: []HTTPieFormField{}}},
+	}
+
+	var method, rawURL, uploadFile string
+	var data, urlencoded []string
+	var formFields []HTTPieFormField
+	var binaryFile string
+	var getMode, jsonMode bool
+
+	addHeader := func(name, value string) {
+		req.Headers = append(req.Headers, HTTPieHeader{Name: name, Value: value, Enabled: true})
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		flagName, value, hasValue := arg, "", false
+
+		// Split "-XPOST" and bundles like "-sSX POST"
+		if len(arg) > 2 && arg[0] == '-' && arg[1] != '-' {
+			for j := 1; j < len(arg); j++ {
+				if strings.IndexByte(curlShortFlagsWithValue, arg[j]) >= 0 {
+					flagName, value, hasValue = "-"+string(arg[j]), arg[j+1:], j+1 < len(arg)
+					break
+				}
+				flagName = "-" + string(arg[j])
+			}
+		}
+
+		takesValue := (len(flagName) == 2 && flagName[0] == '-' && strings.IndexByte(curlShortFlagsWithValue, flagName[1]) >= 0) ||
+			curlLongFlagsWithValue[flagName] || isCurlDataFlag(flagName) ||
+			flagName == "--request" || flagName == "--header" || flagName == "--form" || flagName == "--form-string" ||
+			flagName == "--user" || flagName == "--url" || flagName == "--data-urlencode"
+		if takesValue && !hasValue {
+			if i+1 >= len(args) {
+				return req, fmt.Errorf("option %s needs a value", flagName)
+			}
+			i++
+			value = args[i]
+		}
+
+		switch {
+		case flagName == "-X" || flagName == "--request":
+			method = strings.ToUpper(value)
+		case flagName == "-H" || flagName == "--header":
+			name, headerValue, _ := strings.Cut(value, ":")
+			addHeader(strings.TrimSpace(name), strings.TrimSpace(headerValue))
+		case flagName == "--data-urlencode":
+			urlencoded = append(urlencoded, value)
+		case isCurlDataFlag(flagName):
+			jsonMode = jsonMode || flagName == "--json"
+			if strings.HasPrefix(value, "@") && flagName != "--data-raw" {
+				binaryFile = value[1:]
+			} else {
+				data = append(data, value)
+			}
+		case flagName == "-F" || flagName == "--form":
+			formFields = append(formFields, curlFormField(value))
+		case flagName == "--form-string":
+			// Values are literal: a leading @ or < does not read a file
+			name, fieldValue, _ := strings.Cut(value, "=")
+			formFields = append(formFields, HTTPieFormField{Name: name, Value: fieldValue, Enabled: true})
+		case flagName == "-u" || flagName == "--user":
+			username, password, _ := strings.Cut(value, ":")
+			req.Auth = HTTPieAuth{Type: "basic", Credentials: HTTPieAuthCredentials{Username: username, Password: password}}
+		case flagName == "--oauth2-bearer":
+			req.Auth = HTTPieAuth{Type: "bearer", Credentials: HTTPieAuthCredentials{Password: value}}
+		case flagName == "-A" || flagName == "--user-agent":
+			addHeader("User-Agent", value)
+		case flagName == "-e" || flagName == "--referer":
+			addHeader("Referer", value)
+		case flagName == "-b" || flagName == "--cookie":
+			addHeader("Cookie", value)
+		case flagName == "-T" || flagName == "--upload-file":
+			uploadFile = value
+		case flagName == "-G" || flagName == "--get":
+			getMode = true
+		case flagName == "-I" || flagName == "--head":
+			method = "HEAD"
+		case flagName == "--url":
+			rawURL = value
+		case strings.HasPrefix(arg, "-") && arg != "-":
+			// --compressed, -s, -L, -k, -v and other options that do not
+			// change the request are accepted and ignored
+		default:
+			if rawURL == "" {
+				rawURL = arg
+			}
+		}
+	}
+
+	if rawURL == "" {
+		return req, fmt.Errorf("no URL in command")
+	}
+
+	hasBody := len(data) > 0 || len(urlencoded) > 0 || len(formFields) > 0 || binaryFile != "" || uploadFile != ""
+	if getMode && (len(data) > 0 || len(urlencoded) > 0) {
+		// -G sends the data as the query string
+		query := append(append([]string(nil), data...), encodeCurlURLEncoded(urlencoded)...)
+		rawURL = appendRawQuery(rawURL, strings.Join(query, "&"))
+		data, urlencoded, hasBody = nil, nil, len(formFields) > 0 || binaryFile != "" || uploadFile != ""
+	}
+
+	switch {
+	case method != "":
+	case uploadFile != "":
+		method = "PUT"
+	case hasBody:
+		method = "POST"
+	default:
+		method = "GET"
+	}
+
+	req.Method = method
+	req.URL = rawURL
+
+	contentType := ""
+	for _, header := range req.Headers {
+		if strings.EqualFold(header.Name, "Content-Type") {
+			contentType = header.Value
+		}
+	}
+	if jsonMode && contentType == "" {
+		// --json implies JSON request and response headers
+		contentType = "application/json"
+		addHeader("Content-Type", contentType)
+		addHeader("Accept", contentType)
+	}
+
+	switch {
+	case len(formFields) > 0:
+		req.Body.Type = "form"
+		req.Body.Form.IsMultipart = true
+		req.Body.Form.Fields = formFields
+	case binaryFile != "" || uploadFile != "":
+		req.Body.Type = "file"
+		req.Body.File.Name = binaryFile
+		if uploadFile != "" {
+			req.Body.File.Name = uploadFile
+		}
+	case len(urlencoded) > 0 || (len(data) > 0 && (contentType == "" || strings.Contains(contentType, "x-www-form-urlencoded"))):
+		fields, ok := curlFormFields(data, urlencoded)
+		if ok {
+			req.Body.Type = "form"
+			req.Body.Form.Fields = fields
+			break
+		}
+		req.Body.Type = "text"
+		req.Body.Text = HTTPieText{
+			Value:  strings.Join(append(data, encodeCurlURLEncoded(urlencoded)...), "&"),
+			Format: "application/x-www-form-urlencoded",
+		}
+	case len(data) > 0:
+		req.Body.Type = "text"
+		req.Body.Text = HTTPieText{Value: strings.Join(data, "&"), Format: contentType}
+	}
+
+	return req, nil
+}
+
+func isCurlDataFlag(flagName string) bool {
+	switch flagName {
+	case "-d", "--data", "--data-raw", "--data-ascii", "--data-binary", "--json":
+		return true
+	}
+	return false
+}
+
+// curlFormField parses a -F name=value field. A value starting with @ or <
+// is a file upload.
+func curlFormField(value string) HTTPieFormField {
+	name, fieldValue, _ := strings.Cut(value, "=")
+	field := HTTPieFormField{Name: name, Enabled: true}
+	if strings.HasPrefix(fieldValue, "@") || strings.HasPrefix(fieldValue, "<") {
+		field.Type = "file"
+		fieldValue = fieldValue[1:]
+	}
+	field.Value = stripCurlFormModifiers(fieldValue)
+	return field
+}
+
+// curlFormModifierRegex matches the first ;type=, ;filename=, ;headers= or
+// ;encoder= modifier curl parses out of a -F value.
+var curlFormModifierRegex = regexp.MustCompile(`;\s*(type|filename|headers|encoder)=`)
+
+// stripCurlFormModifiers returns a -F value without its modifiers. A value
+// in double quotes is taken up to the closing quote, as curl does.
+func stripCurlFormModifiers(value string) string {
+	if strings.HasPrefix(value, `"`) {
+		var b strings.Builder
+		for i := 1; i < len(value); i++ {
+			switch value[i] {
+			case '\\':
+				if i+1 < len(value) {
+					i++
+					b.WriteByte(value[i])
+				}
+			case '"':
+				return b.String()
+			default:
+				b.WriteByte(value[i])
+			}
+		}
+		return b.String()
+	}
+	if loc := curlFormModifierRegex.FindStringIndex(value); loc != nil {
+		return value[:loc[0]]
+	}
+	return value
+}
+
+// curlPlainFormComponent matches keys and values that are sent the same
+// whether or not they are re-encoded: unreserved characters and {{variable}}
+// references.
+var curlPlainFormComponent = regexp.MustCompile(`^(?:[A-Za-z0-9._~-]|\{\{[^{}]+\}\})*$`)
+
+// curlFormFields turns -d "a=1&b=2" and --data-urlencode values into form
+// fields. It reports false if some -d data is a JSON object or array, or
+// is not made of plain key=value pairs, so re-encoding it as a form could
+// change the bytes sent.
+func curlFormFields(data, urlencoded []string) ([]HTTPieFormField, bool) {
+	var fields []HTTPieFormField
+	for _, chunk := range data {
+		if trimmed := strings.TrimSpace(chunk); (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+			return nil, false
+		}
+		for _, pair := range strings.Split(chunk, "&") {
+			key, value, found := strings.Cut(pair, "=")
+			if !found || key == "" || !curlPlainFormComponent.MatchString(key) || !curlPlainFormComponent.MatchString(value) {
+				return nil, false
+			}
+			fields = append(fields, HTTPieFormField{Name: key, Value: value, Enabled: true})
+		}
+	}
+	for _, item := range urlencoded {
+		key, value, found := strings.Cut(item, "=")
+		if !found {
+			// "content" without a name is sent as-is
+			key, value = item, ""
+		}
+		fields = append(fields, HTTPieFormField{Name: key, Value: value, Enabled: true})
+	}
+	return fields, true
+}
+
+// encodeCurlURLEncoded applies curl's --data-urlencode encoding to values
+// of the form "name=content" or "content".
+func encodeCurlURLEncoded(items []string) []string {
+	var encoded []string
+	for _, item := range items {
+		if key, value, found := strings.Cut(item, "="); found {
+			encoded = append(encoded, key+"="+url.QueryEscape(value))
+		} else {
+			encoded = append(encoded, url.QueryEscape(item))
+		}
+	}
+	return encoded
+}
+
+// shellCommand is one command from a script, with the comment directly
+// above it.
+type shellCommand struct {
+	args    []string
+	comment string
+}
+
+// splitShellCommands tokenizes shell text into commands. It understands
+// single, double and $'...' quoting, backslash line continuations,
+// comments, and ;, && and || separators. It is not a full shell parser.
+func splitShellCommands(text string) []shellCommand {
+	var commands []shellCommand
+	var current shellCommand
+	var word strings.Builder
+	inWord := false
+	lastComment := ""
+
+	endWord := func() {
+		if inWord {
+			current.args = append(current.args, word.String())
+			word.Reset()
+			inWord = false
+		}
+	}
+	endCommand := func() {
+		endWord()
+		if len(current.args) > 0 {
+			commands = append(commands, current)
+		}
+		current = shellCommand{}
+	}
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '\\' && i+1 < len(text) && (text[i+1] == '\n' || text[i+1] == '\r'):
+			// Line continuation
+			i++
+			if text[i] == '\r' && i+1 < len(text) && text[i+1] == '\n' {
+				i++
+			}
+		case c == '\\' && i+1 < len(text):
+			i++
+			word.WriteByte(text[i])
+			inWord = true
+		case c == '\'':
+			end := strings.IndexByte(text[i+1:], '\'')
+			if end < 0 {
+				end = len(text) - i - 1
+			}
+			word.WriteString(text[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '$' && i+1 < len(text) && text[i+1] == '\'':
+			i = readANSIQuoted(text, i+2, &word)
+			inWord = true
+		case c == '"':
+			for i++; i < len(text) && text[i] != '"'; i++ {
+				if text[i] == '\\' && i+1 < len(text) && strings.IndexByte("\"\\$`\n", text[i+1]) >= 0 {
+					i++
+					if text[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteByte(text[i])
+			}
+			inWord = true
+		case c == '#' && !inWord:
+			end := strings.IndexByte(text[i:], '\n')
+			if end < 0 {
+				end = len(text) - i
+			}
+			comment := strings.TrimSpace(text[i+1 : i+end])
+			if len(current.args) == 0 && !strings.HasPrefix(comment, "!") {
+				lastComment = comment
+			}
+			i += end - 1
+		case c == '\n' || c == ';' || (c == '&' && i+1 < len(text) && text[i+1] == '&') || (c == '|' && i+1 < len(text) && text[i+1] == '|'):
+			if c == '&' || c == '|' {
+				i++
+			}
+			if len(current.args) == 0 && !inWord {
+				if c == '\n' && i > 0 && text[i-1] == '\n' {
+					// A blank line detaches a comment from the next command
+					lastComment = ""
+				}
+				continue
+			}
+			current.comment = lastComment
+			lastComment = ""
+			endCommand()
+		case c == ' ' || c == '\t' || c == '\r':
+			endWord()
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	current.comment = lastComment
+	endCommand()
+
+	return commands
+}
+
+// readANSIQuoted reads a bash $'...' string starting after the opening
+// quote and returns the index of the closing quote.
+func readANSIQuoted(text string, i int, word *strings.Builder) int {
+	escapes := map[byte]string{'n': "\n", 't': "\t", 'r': "\r", '\\': "\\", '\'': "'", '"': "\"", '0': "\x00", 'e': "\x1b", 'a': "\a", 'b': "\b", 'f': "\f", 'v': "\v"}
+	for ; i < len(text) && text[i] != '\''; i++ {
+		if text[i] != '\\' || i+1 >= len(text) {
+			word.WriteByte(text[i])
+			continue
+		}
+		i++
+		switch {
+		case escapes[text[i]] != "":
+			word.WriteString(escapes[text[i]])
+		case text[i] == 'x' && i+2 < len(text):
+			var b byte
+			if _, err := fmt.Sscanf(text[i+1:i+3], "%02x", &b); err == nil {
+				word.WriteByte(b)
+				i += 2
+			} else {
+				word.WriteString("\\x")
+			}
+		case text[i] == 'u' && i+4 < len(text):
+			var r rune
+			if _, err := fmt.Sscanf(text[i+1:i+5], "%04x", &r); err == nil {
+				word.WriteRune(r)
+				i += 4
+			} else {
+				word.WriteString("\\u")
+			}
+		default:
+			word.WriteByte('\\')
+			word.WriteByte(text[i])
+		}
+	}
+	return i
+}
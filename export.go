@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Helpers shared by the exporters (curl, HAR, ...) that render each request
+// of a collection.
+
+// requestVisitor is called for every request with the names of the folders
+// that contain it and the auth it effectively uses (after inheritance).
+type requestVisitor func(item PostmanItem, folders []string, auth *PostmanAuth)
+
+// walkRequests visits every request in the collection, depth first, in
+// collection order.
+func walkRequests(collection PostmanCollection, visit requestVisitor) {
+	walkItems(collection.Item, nil, collection.Auth, visit)
+}
+
+func walkItems(items []PostmanItem, folders []string, auth *PostmanAuth, visit requestVisitor) {
+	for _, item := range items {
+		if item.Request == nil {
+			folderAuth := auth
+			if item.Auth != nil {
+				folderAuth = item.Auth
+			}
+			walkItems(item.Item, append(append([]string(nil), folders...), item.Name), folderAuth, visit)
+			continue
+		}
+
+		requestAuth := auth
+		if item.Request.Auth != nil {
+			requestAuth = item.Request.Auth
+		}
+		if requestAuth != nil && requestAuth.Type == "noauth" {
+			requestAuth = nil
+		}
+		visit(item, folders, requestAuth)
+	}
+}
+
+// registerVariableFlags adds the -resolve and -env flags used by exporters
+// that can substitute {{variables}}.
+func registerVariableFlags(fs *flag.FlagSet, resolve *bool, env *string) {
+	fs.BoolVar(resolve, "resolve", false, "substitute {{variables}} using the collection variables")
+	fs.StringVar(env, "env", "", "substitute {{variables}} using this Postman environment file, or the named HTTPie environment of the input (implies -resolve)")
+}
+
+// loadVariables returns the values used to resolve {{variables}}: the
+// collection variables, overridden by the environment named by env. env is
+// either a Postman environment file or the name of an environment in the
+// HTTPie (or Insomnia) input workspace.
+func loadVariables(collection PostmanCollection, inputFile string, env string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, v := range collection.Variable {
+		values[v.Key] = v.Value
+	}
+	if env == "" {
+		return values, nil
+	}
+
+	if data, err := os.ReadFile(env); err == nil {
+		var postmanEnv PostmanEnvironment
+		if err := json.Unmarshal(data, &postmanEnv); err != nil {
+			return nil, fmt.Errorf("parsing Postman environment %s: %w", env, err)
+		}
+		for _, v := range postmanEnv.Values {
+			if v.Enabled {
+				values[v.Key] = v.Value
+			}
+		}
+		return values, nil
+	}
+
+	data, err := readInput(inputFile)
+	if err != nil {
+		return nil, err
+	}
+	if isWorkspaceFormat(detectInputFormat(data)) {
+		httpieWorkspace, err := parseWorkspace(data, inputFile)
+		if err != nil {
+			return nil, err
+		}
+		for _, httpieEnv := range httpieWorkspace.Environments {
+			if httpieEnv.Name == env {
+				for _, v := range httpieEnv.Variables {
+					values[v.Name] = v.Value
+				}
+				return values, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("environment %q is neither a Postman environment file nor an HTTPie environment in %s", env, inputFile)
+}
+
+var templateVariableRegex = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+
+// resolveVariables replaces {{name}} references with their values. Unknown
+// variables are left as-is. A nil map disables substitution.
+func resolveVariables(text string, values map[string]string) string {
+	if values == nil {
+		return text
+	}
+	return templateVariableRegex.ReplaceAllStringFunc(text, func(match string) string {
+		name := templateVariableRegex.FindStringSubmatch(match)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// requestURL returns the request URL with path variables (:name) filled in
+// and API key query auth appended.
+func requestURL(req *PostmanRequest, auth *PostmanAuth) string {
+	raw := req.URL.Raw
+	if raw == "" {
+		raw = postmanURLString(req.URL)
+	}
+
+	for _, variable := range req.URL.Variable {
+		segmentRegex := regexp.MustCompile(`/:` + regexp.QuoteMeta(variable.Key) + `([/?#]|$)`)
+		raw = segmentRegex.ReplaceAllString(raw, "/"+strings.ReplaceAll(variable.Value, "$", "$$")+"$1")
+	}
+
+	if auth != nil && auth.Type == "apikey" && postmanAuthValue(auth.APIKey, "in") == "query" {
+		raw = appendRawQuery(raw, postmanAuthValue(auth.APIKey, "key")+"="+postmanAuthValue(auth.APIKey, "value"))
+	}
+	return raw
+}
+
+// authHeader returns the header a bearer or header API key auth adds, or
+// empty strings for other auth types.
+func authHeader(auth *PostmanAuth) (string, string) {
+	if auth == nil {
+		return "", ""
+	}
+	switch auth.Type {
+	case "bearer":
+		return "Authorization", "Bearer " + postmanAuthValue(auth.Bearer, "token")
+	case "apikey":
+		if postmanAuthValue(auth.APIKey, "in") != "query" {
+			return postmanAuthValue(auth.APIKey, "key"), postmanAuthValue(auth.APIKey, "value")
+		}
+	}
+	return "", ""
+}
+
+// graphQLPayload renders a GraphQL body as the JSON document sent on the wire.
+func graphQLPayload(gql *PostmanGraphQL) string {
+	payload := map[string]any{"query": gql.Query}
+	if strings.TrimSpace(gql.Variables) != "" {
+		var variables any
+		if err := json.Unmarshal([]byte(gql.Variables), &variables); err == nil {
+			payload["variables"] = variables
+		} else {
+			payload["variables"] = gql.Variables
+		}
+	}
+	data, _ := json.Marshal(payload)
+	return string(data)
+}
+
+// folderFileName joins folder names into a single safe file name.
+func folderFileName(collectionName string, folders []string) string {
+	parts := folders
+	if len(parts) == 0 {
+		parts = []string{collectionName}
+	}
+
+	var names []string
+	for _, part := range parts {
+		if name := sanitizeFilename(part); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "requests"
+	}
+	return strings.Join(names, " - ")
+}
@@ -2,4 +2,6 @@ module github.com/vuon9/postmanzier
 
 go 1.24.3
 
-require github.com/google/uuid v1.6.0 // indirect
+require github.com/google/uuid v1.6.0
+
+require gopkg.in/yaml.v3 v3.0.1
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+func handleToHARCommand() {
+	fs := flag.NewFlagSet("to-har", flag.ExitOnError)
+	var resolve bool
+	var env string
+	registerVariableFlags(fs, &resolve, &env)
+	fs.BoolVar(&options.Deterministic, "deterministic", false, "use a fixed start time so identical inputs give identical output")
+	args := parseArgs(fs, os.Args[2:])
+
+	if len(args) < 2 {
+		fmt.Println("Usage: postmanzier to-har [-resolve] [-env <environment>] [-deterministic] <input-collection> <output-har-file>")
+		fmt.Println("Example: postmanzier to-har -env Production collection.json requests.har")
+		os.Exit(1)
+	}
+
+	inputFile := args[0]
+	collection, err := loadCollection(inputFile)
+	if err != nil {
+		log.Fatalf("Error reading input collection: %v", err)
+	}
+
+	var values map[string]string
+	if resolve || env != "" {
+		if values, err = loadVariables(collection, inputFile, env); err != nil {
+			log.Fatalf("Error loading variables: %v", err)
+		}
+	}
+
+	har := convertPostmanToHAR(collection, values)
+
+	unresolved := 0
+	for _, entry := range har.Log.Entries {
+		if templateVariableRegex.MatchString(entry.Request.URL) {
+			unresolved++
+		}
+	}
+	if unresolved > 0 {
+		log.Printf("Warning: %d request URLs still contain {{variables}}; use -resolve or -env to substitute them", unresolved)
+	}
+
+	outputData, err := json.MarshalIndent(har, "", "  ")
+	if err != nil {
+		log.Fatalf("Error marshaling HAR file: %v", err)
+	}
+
+	finalOutputPath := generateUniqueFilename(args[1])
+	if err := os.WriteFile(finalOutputPath, outputData, 0644); err != nil {
+		log.Fatalf("Error writing output file: %v", err)
+	}
+
+	fmt.Println("HAR export completed!")
+	fmt.Printf("* Total APIs: %d\n", countPostmanRequests(collection.Item))
+	fmt.Printf("* Total entries: %d\n", len(har.Log.Entries))
+	fmt.Printf("--> Output file: %s\n", finalOutputPath)
+}
+
+// convertPostmanToHAR writes the request side of every item as a HAR 1.2
+// entry. Responses are left empty, as nothing was recorded; the request's
+// folder path and name are kept in the request comment.
+func convertPostmanToHAR(collection PostmanCollection, values map[string]string) HARFile {
+	har := HARFile{
+		Log: HARLog{
+			Version: "1.2",
+			Creator: HARCreator{Name: "postmanzier", Version: "1.0"},
+			Entries: []HAREntry{},
+		},
+	}
+
+	started := time.Now().UTC()
+	if options.Deterministic {
+		started = time.Unix(0, 0).UTC()
+	}
+
+	walkRequests(collection, func(item PostmanItem, folders []string, auth *PostmanAuth) {
+		request := convertRequestToHAR(item.Request, auth, values)
+		request.Comment = strings.Join(append(append([]string(nil), folders...), item.Name), " / ")
+
+		har.Log.Entries = append(har.Log.Entries, HAREntry{
+			StartedDateTime: started.Format(time.RFC3339Nano),
+			Request:         request,
+			Response: HARResponse{
+				Cookies:     []HARNameValue{},
+				Headers:     []HARNameValue{},
+				HeadersSize: -1,
+				BodySize:    -1,
+			},
+		})
+	})
+
+	return har
+}
+
+// convertRequestToHAR renders a request as it would be sent, with auth
+// applied as headers or query parameters.
+func convertRequestToHAR(req *PostmanRequest, auth *PostmanAuth, values map[string]string) HARRequest {
+	resolve := func(s string) string {
+		return resolveVariables(s, values)
+	}
+
+	method := strings.ToUpper(req.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	harReq := HARRequest{
+		Method:      method,
+		URL:         resolve(requestURL(req, auth)),
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []HARNameValue{},
+		Headers:     []HARNameValue{},
+		QueryString: []HARNameValue{},
+		HeadersSize: -1,
+		BodySize:    0,
+	}
+
+	if _, rawQuery, found := strings.Cut(harReq.URL, "?"); found {
+		rawQuery, _, _ = strings.Cut(rawQuery, "#")
+		for _, pair := range strings.Split(rawQuery, "&") {
+			if pair == "" {
+				continue
+			}
+			name, value, _ := strings.Cut(pair, "=")
+			harReq.QueryString = append(harReq.QueryString, HARNameValue{Name: unescapeQueryComponent(name), Value: unescapeQueryComponent(value)})
+		}
+	}
+
+	contentType := ""
+	for _, header := range req.Header {
+		if header.Disabled {
+			continue
+		}
+		name, value := resolve(header.Key), resolve(header.Value)
+		harReq.Headers = append(harReq.Headers, HARNameValue{Name: name, Value: value})
+		switch strings.ToLower(name) {
+		case "content-type":
+			contentType = value
+		case "cookie":
+			for _, cookie := range strings.Split(value, ";") {
+				if cookieName, cookieValue, found := strings.Cut(strings.TrimSpace(cookie), "="); found {
+					harReq.Cookies = append(harReq.Cookies, HARNameValue{Name: cookieName, Value: cookieValue})
+				}
+			}
+		}
+	}
+
+	if key, value := authHeader(auth); key != "" {
+		harReq.Headers = append(harReq.Headers, HARNameValue{Name: resolve(key), Value: resolve(value)})
+	} else if auth != nil && auth.Type == "basic" {
+		credentials := resolve(postmanAuthValue(auth.Basic, "username") + ":" + postmanAuthValue(auth.Basic, "password"))
+		harReq.Headers = append(harReq.Headers, HARNameValue{Name: "Authorization", Value: "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))})
+	}
+
+	if postData := convertBodyToHAR(req.Body, contentType, resolve); postData != nil {
+		harReq.PostData = postData
+		harReq.BodySize = len(postData.Text)
+	}
+	return harReq
+}
+
+// convertBodyToHAR maps a Postman body to HAR postData. contentType is the
+// request's Content-Type header, if any; otherwise it is derived from the
+// body mode.
+func convertBodyToHAR(body *PostmanBody, contentType string, resolve func(string) string) *HARPostData {
+	if body == nil {
+		return nil
+	}
+
+	switch body.Mode {
+	case "raw":
+		if body.Raw == "" {
+			return nil
+		}
+		if contentType == "" {
+			contentType = "text/plain"
+			if body.Options != nil && body.Options.Raw.Language == "json" {
+				contentType = "application/json"
+			}
+		}
+		return &HARPostData{MimeType: contentType, Text: resolve(body.Raw)}
+	case "urlencoded":
+		if contentType == "" {
+			contentType = "application/x-www-form-urlencoded"
+		}
+		postData := &HARPostData{MimeType: contentType, Params: []HARPostParam{}}
+		var pairs []string
+		for _, param := range body.URLEncoded {
+			if param.Disabled {
+				continue
+			}
+			name, value := resolve(param.Key), resolve(param.Value)
+			postData.Params = append(postData.Params, HARPostParam{Name: name, Value: value})
+			pairs = append(pairs, url.QueryEscape(name)+"="+url.QueryEscape(value))
+		}
+		postData.Text = strings.Join(pairs, "&")
+		return postData
+	case "formdata":
+		if contentType == "" {
+			contentType = "multipart/form-data"
+		}
+		postData := &HARPostData{MimeType: contentType, Params: []HARPostParam{}}
+		for _, param := range body.FormData {
+			if param.Disabled {
+				continue
+			}
+			if param.Type == "file" {
+				postData.Params = append(postData.Params, HARPostParam{Name: resolve(param.Key), FileName: resolve(param.Src)})
+			} else {
+				postData.Params = append(postData.Params, HARPostParam{Name: resolve(param.Key), Value: resolve(param.Value)})
+			}
+		}
+		return postData
+	case "graphql":
+		if body.GraphQL == nil {
+			return nil
+		}
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		return &HARPostData{MimeType: contentType, Text: resolve(graphQLPayload(body.GraphQL))}
+	case "file":
+		// HAR has no way to reference a file; the contents are not embedded
+		if body.File == nil || body.File.Src == "" {
+			return nil
+		}
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		return &HARPostData{MimeType: contentType}
+	}
+	return nil
+}
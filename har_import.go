@@ -0,0 +1,430 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// HAR 1.2 structure (http://www.softwareishard.com/blog/har-12-spec/).
+// Only the fields used for conversion are declared.
+type HARFile struct {
+	Log HARLog `json:"log"`
+}
+
+type HARLog struct {
+	Version string     `json:"version"`
+	Creator HARCreator `json:"creator"`
+	Entries []HAREntry `json:"entries"`
+}
+
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type HAREntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+	ResourceType    string      `json:"_resourceType,omitempty"` // Chrome DevTools extension
+}
+
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+	Comment     string         `json:"comment,omitempty"`
+}
+
+type HARResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	Content     HARContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type HARPostData struct {
+	MimeType string         `json:"mimeType"`
+	Params   []HARPostParam `json:"params,omitempty"`
+	Text     string         `json:"text,omitempty"`
+}
+
+type HARPostParam struct {
+	Name        string `json:"name"`
+	Value       string `json:"value,omitempty"`
+	FileName    string `json:"fileName,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+}
+
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type HARTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// parseHARFile parses a HAR document. It returns nil if the data is not HAR.
+func parseHARFile(data []byte) *HARFile {
+	var probe struct {
+		Log *struct {
+			Version string          `json:"version"`
+			Entries json.RawMessage `json:"entries"`
+		} `json:"log"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil || probe.Log == nil || probe.Log.Entries == nil {
+		return nil
+	}
+
+	var har HARFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		return nil
+	}
+	return &har
+}
+
+// harSkippedHeaders are set by the browser or the connection and would only
+// get in the way when replaying a request.
+var harSkippedHeaders = map[string]bool{
+	"host":              true,
+	"content-length":    true,
+	"connection":        true,
+	"accept-encoding":   true,
+	"keep-alive":        true,
+	"transfer-encoding": true,
+	"upgrade":           true,
+}
+
+// harStaticResourceTypes and harStaticExtensions identify static assets.
+var harStaticResourceTypes = map[string]bool{
+	"image": true, "stylesheet": true, "script": true, "font": true, "media": true, "manifest": true,
+}
+
+var harStaticExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".ico": true, ".webp": true, ".avif": true,
+	".css": true, ".js": true, ".mjs": true, ".map": true,
+	".woff": true, ".woff2": true, ".ttf": true, ".otf": true, ".eot": true,
+	".mp4": true, ".webm": true, ".mp3": true, ".wav": true, ".ogg": true,
+}
+
+// convertHARToPostman builds a Postman collection from the entries of a HAR
+// file that pass the -har-* filters. Repeated requests (same method, URL and
+// body) become one request with several saved responses. When entries span
+// several hosts, requests are grouped into one folder per host.
+func convertHARToPostman(har *HARFile, sourcePath string) PostmanCollection {
+	name := strings.TrimSuffix(filepath.Base(sourcePath), filepath.Ext(sourcePath))
+	if sourcePath == "-" || name == "" {
+		name = "HAR import"
+	}
+
+	description := "Converted from HAR"
+	if har.Log.Creator.Name != "" {
+		description += " recorded with " + strings.TrimSpace(har.Log.Creator.Name+" "+har.Log.Creator.Version)
+	}
+
+	collection := PostmanCollection{
+		Info: PostmanInfo{
+			PostmanID:   generatePostmanID("har", sourceNames([]string{sourcePath})[0], name),
+			Name:        name,
+			Description: description,
+			Schema:      "https://schema.getpostman.com/json/collection/v2.1.0/collection.json",
+		},
+		Item: []PostmanItem{},
+	}
+
+	domains := splitCommaList(options.HARDomains)
+	mimeTypes := splitCommaList(options.HARMimeTypes)
+	methods := splitCommaList(options.HARMethods)
+
+	type itemRef struct {
+		host  string
+		index int
+	}
+	var hosts []string
+	byHost := map[string][]PostmanItem{}
+	seen := map[string]itemRef{}
+	skipped := 0
+
+	for _, entry := range har.Log.Entries {
+		u, err := url.Parse(entry.Request.URL)
+		if err != nil || u.Host == "" {
+			log.Printf("Warning: skipping HAR entry with invalid URL %q", entry.Request.URL)
+			skipped++
+			continue
+		}
+		if !harEntryMatches(entry, u, domains, mimeTypes, methods) {
+			skipped++
+			continue
+		}
+
+		request := convertHARRequest(entry.Request)
+		response := convertHARResponse(entry.Response, request)
+
+		bodyKey, _ := json.Marshal(request.Body)
+		key := request.Method + " " + request.URL.Raw + "\n" + string(bodyKey)
+		if ref, ok := seen[key]; ok {
+			item := &byHost[ref.host][ref.index]
+			item.Response = append(item.Response, response)
+			continue
+		}
+
+		host := u.Hostname()
+		if _, ok := byHost[host]; !ok {
+			hosts = append(hosts, host)
+		}
+
+		itemName := request.Method + " " + u.EscapedPath()
+		if u.EscapedPath() == "" {
+			itemName = request.Method + " /"
+		}
+		seen[key] = itemRef{host: host, index: len(byHost[host])}
+		byHost[host] = append(byHost[host], PostmanItem{
+			Name:     itemName,
+			Request:  request,
+			Response: []PostmanResponse{response},
+		})
+	}
+
+	if len(hosts) == 1 {
+		collection.Item = byHost[hosts[0]]
+	} else {
+		for _, host := range hosts {
+			collection.Item = append(collection.Item, PostmanItem{Name: host, Item: byHost[host]})
+		}
+	}
+
+	if skipped > 0 {
+		log.Printf("Skipped %d of %d HAR entries (filters, static assets or invalid URLs)", skipped, len(har.Log.Entries))
+	}
+	return collection
+}
+
+// harEntryMatches applies the domain, MIME type, method and static asset
+// filters to an entry.
+func harEntryMatches(entry HAREntry, u *url.URL, domains, mimeTypes, methods []string) bool {
+	if len(domains) > 0 {
+		host := strings.ToLower(u.Hostname())
+		matched := false
+		for _, domain := range domains {
+			domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	mimeType := strings.ToLower(entry.Response.Content.MimeType)
+	if len(mimeTypes) > 0 {
+		matched := false
+		for _, want := range mimeTypes {
+			if strings.Contains(mimeType, strings.ToLower(want)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(methods) > 0 {
+		matched := false
+		for _, method := range methods {
+			if strings.EqualFold(method, entry.Request.Method) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if !options.HARKeepStatic && isHARStaticAsset(entry, u, mimeType) {
+		return false
+	}
+	return true
+}
+
+// isHARStaticAsset reports whether an entry loads an image, stylesheet,
+// script, font or media file rather than calling an API.
+func isHARStaticAsset(entry HAREntry, u *url.URL, mimeType string) bool {
+	if harStaticResourceTypes[strings.ToLower(entry.ResourceType)] {
+		return true
+	}
+	for _, prefix := range []string{"image/", "font/", "audio/", "video/", "text/css", "text/javascript", "application/javascript", "application/x-javascript", "application/font", "application/x-font"} {
+		if strings.HasPrefix(mimeType, prefix) {
+			return true
+		}
+	}
+	return harStaticExtensions[strings.ToLower(path.Ext(u.Path))]
+}
+
+// convertHARRequest maps a recorded request to a Postman request. Pseudo
+// headers (HTTP/2 ":authority" etc.) and connection headers are dropped.
+func convertHARRequest(harReq HARRequest) *PostmanRequest {
+	request := &PostmanRequest{
+		Method: strings.ToUpper(harReq.Method),
+		URL:    convertURL(harReq.URL),
+	}
+
+	contentType := ""
+	for _, header := range harReq.Headers {
+		lower := strings.ToLower(header.Name)
+		if strings.HasPrefix(header.Name, ":") || harSkippedHeaders[lower] {
+			continue
+		}
+		if lower == "content-type" {
+			contentType = header.Value
+		}
+		request.Header = append(request.Header, PostmanHeader{Key: header.Name, Value: header.Value})
+	}
+
+	if postData := harReq.PostData; postData != nil {
+		if contentType == "" {
+			contentType = postData.MimeType
+		}
+		request.Body = convertHARPostData(postData, contentType)
+	}
+	return request
+}
+
+func convertHARPostData(postData *HARPostData, contentType string) *PostmanBody {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		body := &PostmanBody{Mode: "urlencoded"}
+		params := postData.Params
+		if len(params) == 0 {
+			// Some recorders only keep the text
+			for _, pair := range strings.Split(postData.Text, "&") {
+				if key, value, _ := strings.Cut(pair, "="); key != "" {
+					params = append(params, HARPostParam{Name: unescapeQueryComponent(key), Value: unescapeQueryComponent(value)})
+				}
+			}
+		}
+		for _, param := range params {
+			body.URLEncoded = append(body.URLEncoded, PostmanURLEncodedParam{Key: param.Name, Value: param.Value, Type: "text"})
+		}
+		if len(body.URLEncoded) > 0 {
+			return body
+		}
+	case mediaType == "multipart/form-data" && len(postData.Params) > 0:
+		body := &PostmanBody{Mode: "formdata"}
+		for _, param := range postData.Params {
+			if param.FileName != "" {
+				// The file contents are not recorded; keep the name as a placeholder
+				body.FormData = append(body.FormData, PostmanFormDataParam{Key: param.Name, Type: "file", Src: param.FileName})
+			} else {
+				body.FormData = append(body.FormData, PostmanFormDataParam{Key: param.Name, Value: param.Value, Type: "text"})
+			}
+		}
+		return body
+	}
+
+	if postData.Text == "" {
+		return nil
+	}
+	body := &PostmanBody{Mode: "raw", Raw: postData.Text}
+	if jsonContentTypeRegex.MatchString(mediaType) {
+		body.Options = &PostmanBodyOptions{Raw: PostmanBodyRaw{Language: "json"}}
+	}
+	return body
+}
+
+// convertHARResponse saves a recorded response as a Postman example.
+// Base64 bodies are decoded when they are text; binary bodies are left out.
+func convertHARResponse(harResp HARResponse, request *PostmanRequest) PostmanResponse {
+	status := harResp.StatusText
+	if status == "" {
+		status = http.StatusText(harResp.Status)
+	}
+
+	response := PostmanResponse{
+		Name:            strings.TrimSpace(fmt.Sprintf("%d %s", harResp.Status, status)),
+		OriginalRequest: request,
+		Status:          status,
+		Code:            harResp.Status,
+		PreviewLanguage: harPreviewLanguage(harResp.Content.MimeType),
+	}
+	for _, header := range harResp.Headers {
+		if !strings.HasPrefix(header.Name, ":") {
+			response.Header = append(response.Header, PostmanHeader{Key: header.Name, Value: header.Value})
+		}
+	}
+
+	body := harResp.Content.Text
+	if harResp.Content.Encoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(body)
+		if err != nil || !utf8.Valid(decoded) {
+			decoded = nil
+		}
+		body = string(decoded)
+	}
+	response.Body = body
+	return response
+}
+
+// harPreviewLanguage picks Postman's preview language for a MIME type.
+func harPreviewLanguage(mimeType string) string {
+	mimeType = strings.ToLower(mimeType)
+	switch {
+	case jsonContentTypeRegex.MatchString(mimeType):
+		return "json"
+	case strings.Contains(mimeType, "html"):
+		return "html"
+	case strings.Contains(mimeType, "xml"):
+		return "xml"
+	case strings.HasPrefix(mimeType, "text/") || strings.Contains(mimeType, "javascript"):
+		return "text"
+	}
+	return ""
+}
+
+// splitCommaList splits a comma-separated flag value, dropping empty items.
+func splitCommaList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
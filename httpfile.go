@@ -0,0 +1,193 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+func handleToHTTPFileCommand() {
+	fs := flag.NewFlagSet("to-http", flag.ExitOnError)
+	args := parseArgs(fs, os.Args[2:])
+
+	if len(args) < 2 {
+		fmt.Println("Usage: postmanzier to-http <input-collection> <output-http-file>")
+		fmt.Println("Example: postmanzier to-http collection.json requests.http")
+		os.Exit(1)
+	}
+
+	collection, err := loadCollection(args[0])
+	if err != nil {
+		log.Fatalf("Error reading input collection: %v", err)
+	}
+
+	finalOutputPath := generateUniqueFilename(args[1])
+	if err := os.WriteFile(finalOutputPath, []byte(renderHTTPFile(collection)), 0644); err != nil {
+		log.Fatalf("Error writing output file: %v", err)
+	}
+
+	fmt.Println("HTTP file export completed!")
+	fmt.Printf("* Total APIs: %d\n", countPostmanRequests(collection.Item))
+	fmt.Printf("* Total variables: %d\n", len(collection.Variable))
+	fmt.Printf("--> Output file: %s\n", finalOutputPath)
+}
+
+// httpFileBoundary separates the parts of multipart bodies.
+const httpFileBoundary = "PostmanzierBoundary"
+
+var httpFileIdentifierRegex = regexp.MustCompile(`[^A-Za-z0-9_\-]+`)
+
+// renderHTTPFile renders a collection as a .http file: collection variables
+// as @name = value declarations, then one ### block per request titled with
+// its folder path, with a # @name annotation.
+func renderHTTPFile(collection PostmanCollection) string {
+	var b strings.Builder
+	b.WriteString("# " + shellComment(collection.Info.Name) + "\n")
+
+	if len(collection.Variable) > 0 {
+		b.WriteString("\n")
+		for _, v := range collection.Variable {
+			b.WriteString("@" + v.Key + " = " + strings.ReplaceAll(v.Value, "\n", " ") + "\n")
+		}
+	}
+
+	usedNames := map[string]bool{}
+	walkRequests(collection, func(item PostmanItem, folders []string, auth *PostmanAuth) {
+		title := strings.Join(append(append([]string(nil), folders...), item.Name), " / ")
+		b.WriteString("\n### " + shellComment(title) + "\n")
+		b.WriteString("# @name " + httpFileIdentifier(item.Name, usedNames) + "\n")
+		b.WriteString(httpFileRequest(item.Request, auth))
+	})
+
+	return b.String()
+}
+
+// httpFileRequest renders the request line, headers and body of a request.
+func httpFileRequest(req *PostmanRequest, auth *PostmanAuth) string {
+	method := strings.ToUpper(req.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	var b strings.Builder
+	b.WriteString(method + " " + requestURL(req, auth) + "\n")
+
+	body, contentType := httpFileBody(req.Body)
+	for _, header := range req.Header {
+		if contentType != "" && strings.EqualFold(header.Key, "Content-Type") {
+			// Replaced by the Content-Type the body needs (e.g. with a boundary)
+			continue
+		}
+		line := header.Key + ": " + header.Value
+		if header.Disabled {
+			line = "# " + line
+		}
+		b.WriteString(line + "\n")
+	}
+	if contentType != "" {
+		b.WriteString("Content-Type: " + contentType + "\n")
+	}
+
+	if key, value := authHeader(auth); key != "" {
+		b.WriteString(key + ": " + value + "\n")
+	} else if auth != nil && auth.Type == "basic" {
+		// "user password" is encoded by the clients; "user:password" is sent
+		// verbatim by some of them
+		credentials := strings.TrimSpace(postmanAuthValue(auth.Basic, "username") + " " + postmanAuthValue(auth.Basic, "password"))
+		b.WriteString("Authorization: Basic " + credentials + "\n")
+	}
+
+	if body != "" {
+		b.WriteString("\n" + body + "\n")
+	}
+	return b.String()
+}
+
+// httpFileBody renders a body, returning the Content-Type it needs when
+// that differs from the request headers (forms, GraphQL); "" otherwise.
+func httpFileBody(body *PostmanBody) (string, string) {
+	if body == nil {
+		return "", ""
+	}
+
+	switch body.Mode {
+	case "raw":
+		return body.Raw, ""
+	case "urlencoded":
+		var pairs []string
+		for _, param := range body.URLEncoded {
+			if !param.Disabled {
+				pairs = append(pairs, escapeOutsideVariables(param.Key)+"="+escapeOutsideVariables(param.Value))
+			}
+		}
+		if len(pairs) == 0 {
+			return "", ""
+		}
+		return strings.Join(pairs, "&"), "application/x-www-form-urlencoded"
+	case "formdata":
+		var b strings.Builder
+		for _, param := range body.FormData {
+			if param.Disabled {
+				continue
+			}
+			b.WriteString("--" + httpFileBoundary + "\n")
+			if param.Type == "file" {
+				fileName := param.Src[strings.LastIndexAny(param.Src, `/\`)+1:]
+				b.WriteString(fmt.Sprintf("Content-Disposition: form-data; name=%q; filename=%q\n\n", param.Key, fileName))
+				b.WriteString("< " + param.Src + "\n")
+			} else {
+				b.WriteString(fmt.Sprintf("Content-Disposition: form-data; name=%q\n\n", param.Key))
+				b.WriteString(param.Value + "\n")
+			}
+		}
+		if b.Len() == 0 {
+			return "", ""
+		}
+		b.WriteString("--" + httpFileBoundary + "--")
+		return b.String(), "multipart/form-data; boundary=" + httpFileBoundary
+	case "graphql":
+		if body.GraphQL == nil {
+			return "", ""
+		}
+		return graphQLPayload(body.GraphQL), "application/json"
+	case "file":
+		if body.File == nil || body.File.Src == "" {
+			return "", ""
+		}
+		return "< " + body.File.Src, ""
+	}
+	return "", ""
+}
+
+// httpFileIdentifier turns a request name into a unique identifier for
+// # @name, which must not contain spaces.
+func httpFileIdentifier(name string, used map[string]bool) string {
+	base := strings.Trim(httpFileIdentifierRegex.ReplaceAllString(name, "_"), "_")
+	if base == "" {
+		base = "request"
+	}
+	identifier := base
+	for i := 2; used[identifier]; i++ {
+		identifier = fmt.Sprintf("%s_%d", base, i)
+	}
+	used[identifier] = true
+	return identifier
+}
+
+// escapeOutsideVariables percent-encodes a form key or value, leaving
+// {{variable}} references intact.
+func escapeOutsideVariables(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, match := range templateVariableRegex.FindAllStringIndex(s, -1) {
+		b.WriteString(url.QueryEscape(s[last:match[0]]))
+		b.WriteString(s[match[0]:match[1]])
+		last = match[1]
+	}
+	b.WriteString(url.QueryEscape(s[last:]))
+	return b.String()
+}
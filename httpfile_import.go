@@ -0,0 +1,272 @@
+package main
+
+import (
+	"encoding/base64"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// .http/.rest files, as used by the VS Code REST Client and JetBrains HTTP
+// Client: requests separated by ### lines, with @name = value variables.
+
+var (
+	httpFileRequestLineRegex = regexp.MustCompile(`^(GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS|TRACE|CONNECT)\s+(\S+)(?:\s+HTTP/[\d.]+)?\s*$`)
+	httpFileVariableRegex    = regexp.MustCompile(`^@([\w.\-]+)\s*=\s*(.*?)\s*$`)
+	httpFileNameRegex        = regexp.MustCompile(`^(?:#|//)\s*@name\s*[= ]?\s*(.+?)\s*$`)
+	httpFileDetectRegex      = regexp.MustCompile(`(?m)^(?:###|(?:GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS)\s+\S+)`)
+)
+
+// isHTTPFile reports whether data looks like a .http/.rest file.
+func isHTTPFile(data []byte) bool {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return false
+	}
+	return httpFileDetectRegex.MatchString(trimmed)
+}
+
+// convertHTTPFileToWorkspace parses a .http file into an HTTPie workspace,
+// so it goes through the same conversion path as HTTPie requests. File
+// variables become a default environment, and so collection variables.
+func convertHTTPFileToWorkspace(data []byte, sourcePath string) HTTPieWorkspace {
+	name := strings.TrimSuffix(filepath.Base(sourcePath), filepath.Ext(sourcePath))
+	if sourcePath == "-" || name == "" {
+		name = "HTTP requests"
+	}
+
+	workspace := HTTPieWorkspace{
+		Entry: HTTPieEntry{
+			Name: name,
+			Auth: HTTPieAuth{Type: "none"},
+		},
+	}
+	environment := HTTPieEnvironment{Name: name, IsDefault: true, Variables: []HTTPieEnvironmentVariable{}}
+
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	var block []string
+	title := ""
+	flush := func() {
+		if req, ok := parseHTTPFileRequest(block, &environment); ok {
+			// The ### title is the readable name; # @name is an identifier
+			if title != "" {
+				req.Name = title
+			}
+			workspace.Entry.Requests = append(workspace.Entry.Requests, req)
+		}
+		block = nil
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, "###") {
+			flush()
+			title = strings.TrimSpace(strings.TrimLeft(line, "#"))
+			continue
+		}
+		block = append(block, line)
+	}
+	flush()
+
+	if len(environment.Variables) > 0 {
+		workspace.Environments = []HTTPieEnvironment{environment}
+	}
+	return workspace
+}
+
+// parseHTTPFileRequest parses the lines between two ### separators.
+// Variable declarations found before the request line are added to env.
+// It reports false if the block has no request.
+func parseHTTPFileRequest(lines []string, env *HTTPieEnvironment) (HTTPieRequest, bool) {
+	req := HTTPieRequest{
+		Headers:     []HTTPieHeader{},
+		QueryParams: []HTTPieQueryParam{},
+		PathParams:  []HTTPiePathParam{},
+		Auth:        HTTPieAuth{Type: "none"},
+		Body:        HTTPieBody{Type: "none", Form: HTTPieForm{Fields: []HTTPieFormField{}}},
+	}
+
+	i := 0
+	// Comments, annotations and variables before the request line
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if match := httpFileNameRegex.FindStringSubmatch(line); match != nil {
+			req.Name = match[1]
+			continue
+		}
+		if match := httpFileVariableRegex.FindStringSubmatch(line); match != nil {
+			env.Variables = append(env.Variables, HTTPieEnvironmentVariable{Name: match[1], Value: match[2]})
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		break
+	}
+	if i >= len(lines) {
+		return req, false
+	}
+
+	// Request line: "METHOD URL [HTTP/1.1]" or just a URL for GET
+	requestLine := strings.TrimSpace(lines[i])
+	if match := httpFileRequestLineRegex.FindStringSubmatch(requestLine); match != nil {
+		req.Method, req.URL = match[1], match[2]
+	} else {
+		req.Method, req.URL = "GET", strings.Fields(requestLine)[0]
+	}
+	i++
+
+	// Query continuation lines (?a=1 / &b=2)
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, "?") && !strings.HasPrefix(line, "&") {
+			break
+		}
+		req.URL += line
+	}
+
+	// Headers, up to the first blank line
+	contentType := ""
+	for ; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			i++
+			break
+		}
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		name, value, _ := strings.Cut(line, ":")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if strings.EqualFold(name, "Authorization") {
+			if auth, ok := parseHTTPFileAuth(value); ok {
+				req.Auth = auth
+				continue
+			}
+		}
+		if strings.EqualFold(name, "Content-Type") {
+			contentType = value
+		}
+		req.Headers = append(req.Headers, HTTPieHeader{Name: name, Value: value, Enabled: true})
+	}
+
+	// Body, up to the end of the block. Response handlers (> ...) and
+	// response references (<> ...) are dropped.
+	var body []string
+	for ; i < len(lines); i++ {
+		if strings.HasPrefix(lines[i], "> ") || strings.HasPrefix(lines[i], ">>") || strings.HasPrefix(lines[i], "<> ") {
+			break
+		}
+		body = append(body, lines[i])
+	}
+	bodyText := strings.TrimRight(strings.Join(body, "\n"), "\n \t")
+
+	switch {
+	case bodyText == "":
+	case strings.HasPrefix(bodyText, "< ") && !strings.Contains(bodyText, "\n"):
+		req.Body.Type = "file"
+		req.Body.File.Name = strings.TrimSpace(bodyText[2:])
+	case strings.HasPrefix(strings.ToLower(contentType), "multipart/form-data"):
+		if fields, ok := parseHTTPFileMultipart(bodyText, contentType); ok {
+			req.Body.Type = "form"
+			req.Body.Form.IsMultipart = true
+			req.Body.Form.Fields = fields
+			req.Headers = removeHeader(req.Headers, "Content-Type")
+			break
+		}
+		req.Body.Type = "text"
+		req.Body.Text = HTTPieText{Value: bodyText, Format: contentType}
+	case strings.Contains(strings.ToLower(contentType), "x-www-form-urlencoded"):
+		if fields, ok := curlFormFields([]string{strings.ReplaceAll(bodyText, "\n", "")}, nil); ok {
+			req.Body.Type = "form"
+			req.Body.Form.Fields = fields
+			break
+		}
+		fallthrough
+	default:
+		req.Body.Type = "text"
+		req.Body.Text = HTTPieText{Value: bodyText, Format: contentType}
+	}
+
+	return req, true
+}
+
+// parseHTTPFileAuth maps Basic and Bearer Authorization headers to auth.
+// Basic credentials may be written as "user password", "user:password",
+// already base64-encoded, or as a lone user name with no password.
+func parseHTTPFileAuth(value string) (HTTPieAuth, bool) {
+	scheme, credentials, _ := strings.Cut(value, " ")
+	credentials = strings.TrimSpace(credentials)
+
+	switch strings.ToLower(scheme) {
+	case "bearer":
+		return HTTPieAuth{Type: "bearer", Credentials: HTTPieAuthCredentials{Password: credentials}}, credentials != ""
+	case "basic":
+		if username, password, found := strings.Cut(credentials, " "); found {
+			return HTTPieAuth{Type: "basic", Credentials: HTTPieAuthCredentials{Username: username, Password: strings.TrimSpace(password)}}, true
+		}
+		if !strings.Contains(credentials, ":") {
+			if decoded, err := base64.StdEncoding.DecodeString(credentials); err == nil && strings.Contains(string(decoded), ":") {
+				credentials = string(decoded)
+			}
+		}
+		if username, password, found := strings.Cut(credentials, ":"); found {
+			return HTTPieAuth{Type: "basic", Credentials: HTTPieAuthCredentials{Username: username, Password: password}}, true
+		}
+		return HTTPieAuth{Type: "basic", Credentials: HTTPieAuthCredentials{Username: credentials}}, credentials != ""
+	}
+	return HTTPieAuth{}, false
+}
+
+var httpFileDispositionRegex = regexp.MustCompile(`(?i)^Content-Disposition:\s*form-data(.*)$`)
+var httpFileDispositionParamRegex = regexp.MustCompile(`;\s*(name|filename)="([^"]*)"`)
+
+// parseHTTPFileMultipart splits a multipart body written out in a .http
+// file into form fields. Parts whose content is "< path" become file
+// fields. It reports false if the body does not use the boundary.
+func parseHTTPFileMultipart(body, contentType string) ([]HTTPieFormField, bool) {
+	_, boundary, found := strings.Cut(contentType, "boundary=")
+	boundary = strings.Trim(strings.TrimSpace(strings.Split(boundary, ";")[0]), `"`)
+	if !found || boundary == "" || !strings.HasPrefix(body, "--"+boundary) {
+		return nil, false
+	}
+
+	var fields []HTTPieFormField
+	for _, part := range strings.Split(body, "--"+boundary)[1:] {
+		if strings.HasPrefix(part, "--") {
+			break
+		}
+		headerText, content, _ := strings.Cut(strings.TrimPrefix(part, "\n"), "\n\n")
+		field := HTTPieFormField{Enabled: true, Value: strings.TrimSuffix(content, "\n")}
+		for _, header := range strings.Split(headerText, "\n") {
+			match := httpFileDispositionRegex.FindStringSubmatch(header)
+			if match == nil {
+				continue
+			}
+			for _, param := range httpFileDispositionParamRegex.FindAllStringSubmatch(match[1], -1) {
+				if strings.EqualFold(param[1], "name") {
+					field.Name = param[2]
+				}
+			}
+		}
+		if field.Name == "" {
+			return nil, false
+		}
+		if strings.HasPrefix(field.Value, "< ") {
+			field.Type = "file"
+			field.Value = strings.TrimSpace(field.Value[2:])
+		}
+		fields = append(fields, field)
+	}
+	return fields, true
+}
+
+// removeHeader returns headers without those named name.
+func removeHeader(headers []HTTPieHeader, name string) []HTTPieHeader {
+	var kept []HTTPieHeader
+	for _, header := range headers {
+		if !strings.EqualFold(header.Name, name) {
+			kept = append(kept, header)
+		}
+	}
+	return kept
+}
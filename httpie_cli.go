@@ -0,0 +1,407 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+func handleToHTTPieCLICommand() {
+	fs := flag.NewFlagSet("to-httpie-cli", flag.ExitOnError)
+	var env string
+	fs.StringVar(&env, "env", "", "take variable defaults from this environment of the input (default: the default environment)")
+	args := parseArgs(fs, os.Args[2:])
+
+	if len(args) < 2 {
+		fmt.Println("Usage: postmanzier to-httpie-cli [-env <environment>] <input-collection> <output-dir>")
+		fmt.Println("Example: postmanzier to-httpie-cli -env Staging collection.json smoke/")
+		os.Exit(1)
+	}
+
+	inputFile := args[0]
+	data, err := readInput(inputFile)
+	if err != nil {
+		log.Fatalf("Error reading input file: %v", err)
+	}
+
+	// Other formats are converted to the HTTPie model first
+	var httpieWorkspace HTTPieWorkspace
+	if isWorkspaceFormat(detectInputFormat(data)) {
+		if httpieWorkspace, err = parseWorkspace(data, inputFile); err != nil {
+			log.Fatalf("Error parsing input collection: %v", err)
+		}
+	} else {
+		collection, err := loadCollection(inputFile)
+		if err != nil {
+			log.Fatalf("Error reading input collection: %v", err)
+		}
+		httpieWorkspace = convertPostmanToWorkspace(collection, nil)
+	}
+
+	environment, err := selectEnvironment(httpieWorkspace.Environments, env)
+	if err != nil {
+		log.Fatalf("Error selecting environment: %v", err)
+	}
+
+	outputDir := args[1]
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		log.Fatalf("Error creating output directory: %v", err)
+	}
+
+	scripts := renderHTTPieScripts(httpieWorkspace, environment)
+	for _, script := range scripts {
+		path := generateUniqueFilename(filepath.Join(outputDir, script.name+".sh"))
+		if err := os.WriteFile(path, []byte(script.content), 0755); err != nil {
+			log.Fatalf("Error writing output file: %v", err)
+		}
+		fmt.Printf("--> Output file: %s\n", path)
+	}
+
+	fmt.Println("HTTPie CLI export completed!")
+	fmt.Printf("* Total APIs: %d\n", countTotalRequests(httpieWorkspace))
+	fmt.Printf("* Total scripts: %d\n", len(scripts))
+}
+
+// selectEnvironment returns the environment named name, or the default
+// (else first) environment when name is empty. It returns nil if there are
+// no environments.
+func selectEnvironment(environments []HTTPieEnvironment, name string) (*HTTPieEnvironment, error) {
+	for i := range environments {
+		if (name != "" && environments[i].Name == name) || (name == "" && environments[i].IsDefault) {
+			return &environments[i], nil
+		}
+	}
+	if name != "" {
+		return nil, fmt.Errorf("environment %q not found in the input", name)
+	}
+	if len(environments) > 0 {
+		return &environments[0], nil
+	}
+	return nil, nil
+}
+
+// renderHTTPieScripts renders one script for the workspace's own requests
+// and one for each collection, nested collections included. {{variables}}
+// become shell variables whose defaults come from environment and can be
+// overridden from the calling environment.
+func renderHTTPieScripts(workspace HTTPieWorkspace, environment *HTTPieEnvironment) []shellScript {
+	var scripts []shellScript
+
+	var visit func(folders []string, requests []HTTPieRequest, collections []HTTPieCollection, auth HTTPieAuth)
+	visit = func(folders []string, requests []HTTPieRequest, collections []HTTPieCollection, auth HTTPieAuth) {
+		if len(requests) > 0 {
+			r := &httpieScriptRenderer{variables: map[string]bool{}}
+			var body strings.Builder
+			for _, req := range requests {
+				title := req.Name
+				if title == "" {
+					title = req.Method + " " + req.URL
+				}
+				body.WriteString("\n# " + shellComment(title) + "\n")
+				body.WriteString(r.command(req, effectiveHTTPieAuth(req.Auth, auth)) + "\n")
+			}
+
+			header := strings.Join(append([]string{workspace.Entry.Name}, folders...), " / ")
+			scripts = append(scripts, shellScript{
+				name:    folderFileName(workspace.Entry.Name, folders),
+				content: "#!/bin/sh\n# " + shellComment(header) + "\n" + "# Variables can be overridden from the environment.\nset -e\n" + r.variableDefaults(environment) + body.String(),
+			})
+		}
+
+		for _, collection := range collections {
+			visit(append(append([]string(nil), folders...), collection.Name), collection.Requests, collection.Collections, effectiveHTTPieAuth(collection.Auth, auth))
+		}
+	}
+	visit(nil, workspace.Entry.Requests, workspace.Entry.Collections, workspace.Entry.Auth)
+
+	return scripts
+}
+
+// effectiveHTTPieAuth resolves inherited auth against the parent's auth.
+func effectiveHTTPieAuth(auth, parent HTTPieAuth) HTTPieAuth {
+	switch auth.Type {
+	case "", "inherit", "inherited":
+		return parent
+	}
+	return auth
+}
+
+// httpieScriptRenderer renders HTTPie commands, recording the variables
+// they reference.
+type httpieScriptRenderer struct {
+	variables map[string]bool
+}
+
+var shellVariableNameRegex = regexp.MustCompile(`[^A-Z0-9_]+`)
+
+// shellVariableName turns a template variable name into a shell variable
+// name, e.g. base-url becomes BASE_URL.
+func shellVariableName(name string) string {
+	shellName := strings.Trim(shellVariableNameRegex.ReplaceAllString(strings.ToUpper(name), "_"), "_")
+	if shellName == "" || (shellName[0] >= '0' && shellName[0] <= '9') {
+		shellName = "_" + shellName
+	}
+	return shellName
+}
+
+// word quotes s as a single shell word. Text without {{variables}} is
+// single-quoted; otherwise it is double-quoted with the variables expanded.
+func (r *httpieScriptRenderer) word(s string) string {
+	matches := templateVariableRegex.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return shellQuote(s)
+	}
+
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`")
+	var b strings.Builder
+	b.WriteString(`"`)
+	last := 0
+	for _, match := range matches {
+		name := s[match[2]:match[3]]
+		r.variables[name] = true
+		b.WriteString(escape.Replace(s[last:match[0]]))
+		b.WriteString("${" + shellVariableName(name) + "}")
+		last = match[1]
+	}
+	b.WriteString(escape.Replace(s[last:]))
+	b.WriteString(`"`)
+	return b.String()
+}
+
+// variableDefaults renders ": "${NAME=default}"" lines for the variables
+// used so far. Secret variables get no default and must be set.
+func (r *httpieScriptRenderer) variableDefaults(environment *HTTPieEnvironment) string {
+	if len(r.variables) == 0 {
+		return ""
+	}
+
+	values := map[string]HTTPieEnvironmentVariable{}
+	if environment != nil {
+		for _, v := range environment.Variables {
+			values[v.Name] = v
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("\n")
+	for _, name := range sortedKeys(r.variables) {
+		shellName := shellVariableName(name)
+		v := values[name]
+		if v.IsSecret {
+			b.WriteString(fmt.Sprintf(": \"${%s:?set %s (%s)}\"\n", shellName, shellName, shellComment(name)))
+			continue
+		}
+		b.WriteString(fmt.Sprintf(": \"${%s=%s}\"\n", shellName, strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "`", "\\`", "}", `\}`).Replace(v.Value)))
+	}
+	return b.String()
+}
+
+// command renders a request as an http/https command line using HTTPie's
+// request items: Header:value, param==value, field=value, field:=json and
+// field@file.
+func (r *httpieScriptRenderer) command(req HTTPieRequest, auth HTTPieAuth) string {
+	program := "http"
+	if strings.HasPrefix(strings.ToLower(req.URL), "https://") {
+		program = "https"
+	}
+	method := strings.ToUpper(req.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	baseURL, query := httpieSplitQuery(req)
+	for _, param := range req.PathParams {
+		if param.Enabled && param.Name != "" {
+			segmentRegex := regexp.MustCompile(`/(?:\{` + regexp.QuoteMeta(param.Name) + `\}|:` + regexp.QuoteMeta(param.Name) + `)([/?#]|$)`)
+			baseURL = segmentRegex.ReplaceAllString(baseURL, "/"+strings.ReplaceAll(param.Value, "$", "$$")+"$1")
+		}
+	}
+
+	args := []string{program, "--ignore-stdin", "--check-status"}
+	var items []string
+
+	switch auth.Type {
+	case "basic":
+		args = append(args, "--auth", r.word(auth.Credentials.Username+":"+auth.Credentials.Password))
+	case "bearer":
+		args = append(args, "--auth-type", "bearer", "--auth", r.word(auth.Credentials.Password))
+	case "apiKey":
+		if auth.Target == "query" {
+			items = append(items, r.word(httpieEscapeItem(auth.Credentials.Username)+"=="+auth.Credentials.Password))
+		} else {
+			items = append(items, r.word(httpieEscapeItem(auth.Credentials.Username)+":"+auth.Credentials.Password))
+		}
+	}
+
+	switch req.Body.Type {
+	case "form":
+		if req.Body.Form.IsMultipart {
+			args = append(args, "--multipart")
+		} else {
+			args = append(args, "--form")
+		}
+	case "text":
+		if !httpieJSONItems(req.Body.Text.Value) && req.Body.Text.Value != "" {
+			args = append(args, "--raw", r.word(req.Body.Text.Value))
+		}
+	}
+
+	for _, param := range query {
+		items = append(items, r.word(httpieEscapeItem(param.Name)+"=="+param.Value))
+	}
+	for _, header := range req.Headers {
+		if !header.Enabled {
+			continue
+		}
+		if header.Value == "" {
+			items = append(items, r.word(httpieEscapeItem(header.Name)+";"))
+		} else {
+			items = append(items, r.word(httpieEscapeItem(header.Name)+":"+header.Value))
+		}
+	}
+	items = append(items, r.bodyItems(req.Body)...)
+
+	args = append(args, method, r.word(baseURL))
+	return strings.Join(append([]string{strings.Join(args, " ")}, items...), " \\\n  ")
+}
+
+// bodyItems renders form fields, JSON object bodies, GraphQL and file
+// bodies as request items. Other text bodies are sent with --raw.
+func (r *httpieScriptRenderer) bodyItems(body HTTPieBody) []string {
+	var items []string
+	switch body.Type {
+	case "form":
+		for _, field := range body.Form.Fields {
+			if !field.Enabled {
+				continue
+			}
+			if field.Type == "file" {
+				items = append(items, r.word(httpieEscapeItem(field.Name)+"@"+field.Value))
+			} else {
+				items = append(items, r.word(httpieEscapeItem(field.Name)+"="+field.Value))
+			}
+		}
+	case "text":
+		fields, ok := jsonObjectFields(body.Text.Value)
+		if !ok {
+			return nil
+		}
+		for _, field := range fields {
+			items = append(items, r.jsonItem(field.key, field.value))
+		}
+	case "graphql":
+		items = append(items, r.word("query="+body.GraphQL.Query))
+		if variables := strings.TrimSpace(body.GraphQL.Variables); variables != "" {
+			items = append(items, r.word("variables:="+variables))
+		}
+	case "file":
+		if body.File.Name != "" {
+			items = append(items, r.word("@"+body.File.Name))
+		}
+	}
+	return items
+}
+
+// jsonItem renders a top-level JSON field: strings as field=value,
+// anything else as field:=json.
+func (r *httpieScriptRenderer) jsonItem(key string, value json.RawMessage) string {
+	var text string
+	if json.Unmarshal(value, &text) == nil {
+		return r.word(httpieEscapeItem(key) + "=" + text)
+	}
+	var compact bytes.Buffer
+	json.Compact(&compact, value)
+	return r.word(httpieEscapeItem(key) + ":=" + compact.String())
+}
+
+// httpieJSONItems reports whether a text body is a JSON object that can be
+// expressed as request items.
+func httpieJSONItems(text string) bool {
+	_, ok := jsonObjectFields(text)
+	return ok
+}
+
+type jsonField struct {
+	key   string
+	value json.RawMessage
+}
+
+// jsonObjectFields returns the top-level fields of a non-empty JSON object
+// in document order.
+func jsonObjectFields(text string) ([]jsonField, bool) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, false
+	}
+
+	var fields []jsonField
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, false
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, false
+		}
+		fields = append(fields, jsonField{key: token.(string), value: value})
+	}
+	if token, err := decoder.Token(); err != nil || token != json.Delim('}') {
+		return nil, false
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, false
+	}
+	return fields, len(fields) > 0
+}
+
+// httpieSplitQuery returns the URL without its query string, and the
+// query params to send as param==value items: those in the URL that are
+// not disabled in the query param table, then enabled table params not
+// already in the URL.
+func httpieSplitQuery(req HTTPieRequest) (string, []HTTPieQueryParam) {
+	withoutFragment, _, _ := strings.Cut(req.URL, "#")
+	base, rawQuery, _ := strings.Cut(withoutFragment, "?")
+
+	enabled := map[string]bool{}
+	inTable := map[string]bool{}
+	for _, param := range req.QueryParams {
+		inTable[param.Name] = true
+		enabled[param.Name] = enabled[param.Name] || param.Enabled
+	}
+
+	var params []HTTPieQueryParam
+	seen := map[string]bool{}
+	for _, pair := range strings.Split(rawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(pair, "=")
+		name, value = unescapeQueryComponent(name), unescapeQueryComponent(value)
+		if inTable[name] && !enabled[name] {
+			continue
+		}
+		seen[name+"="+value] = true
+		params = append(params, HTTPieQueryParam{Name: name, Value: value, Enabled: true})
+	}
+	for _, param := range req.QueryParams {
+		if param.Enabled && !seen[param.Name+"="+param.Value] {
+			params = append(params, param)
+		}
+	}
+	return base, params
+}
+
+// httpieEscapeItem escapes the separator characters HTTPie looks for in
+// the name part of a request item, including [ which starts a nested JSON
+// path.
+func httpieEscapeItem(name string) string {
+	return strings.NewReplacer(`\`, `\\`, ":", `\:`, "=", `\=`, "@", `\@`, ";", `\;`, "[", `\[`).Replace(name)
+}
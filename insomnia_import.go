@@ -0,0 +1,377 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Insomnia v4 export structure. Every object is a resource linked to its
+// parent through parentId; only the fields used for conversion are declared.
+type InsomniaExport struct {
+	Type         string             `json:"_type"`
+	ExportFormat int                `json:"__export_format"`
+	Resources    []InsomniaResource `json:"resources"`
+}
+
+type InsomniaResource struct {
+	ID             string         `json:"_id"`
+	Type           string         `json:"_type"`
+	ParentID       string         `json:"parentId"`
+	Name           string         `json:"name"`
+	MetaSortKey    float64        `json:"metaSortKey"`
+	URL            string         `json:"url"`
+	Method         string         `json:"method"`
+	Headers        []InsomniaPair `json:"headers"`
+	Parameters     []InsomniaPair `json:"parameters"`
+	Body           InsomniaBody   `json:"body"`
+	Authentication InsomniaAuth   `json:"authentication"`
+	Data           map[string]any `json:"data"`
+	IsPrivate      bool           `json:"isPrivate"`
+	Color          string         `json:"color"`
+}
+
+type InsomniaPair struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled"`
+	Type     string `json:"type,omitempty"`     // "file" for multipart file fields
+	FileName string `json:"fileName,omitempty"` // Path of a multipart file field
+}
+
+type InsomniaBody struct {
+	MimeType string         `json:"mimeType"`
+	Text     string         `json:"text"`
+	Params   []InsomniaPair `json:"params"`
+	FileName string         `json:"fileName"`
+}
+
+type InsomniaAuth struct {
+	Type     string `json:"type"`
+	Disabled bool   `json:"disabled"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Token    string `json:"token"`
+	Prefix   string `json:"prefix"`
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	AddTo    string `json:"addTo"`
+}
+
+// parseInsomniaExport parses an Insomnia v4 export. It returns nil if the
+// data is not one.
+func parseInsomniaExport(data []byte) *InsomniaExport {
+	var export InsomniaExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil
+	}
+	if export.Type != "export" || export.ExportFormat != 4 {
+		return nil
+	}
+	return &export
+}
+
+// insomniaTemplateRegex matches Insomnia's {{ _.name }} and {{ name }}
+// variable references.
+var insomniaTemplateRegex = regexp.MustCompile(`\{\{\s*(?:_\.)?([\w.\-]+)\s*\}\}`)
+
+// convertInsomniaTemplate rewrites Insomnia variable references to Postman's
+// {{name}} form. Template tags ({% ... %}) have no Postman equivalent and
+// are left unchanged.
+func convertInsomniaTemplate(text string) string {
+	return insomniaTemplateRegex.ReplaceAllString(text, "{{$1}}")
+}
+
+// insomniaConverter indexes the resources of an export by parent.
+type insomniaConverter struct {
+	children map[string][]InsomniaResource
+	warned   map[string]bool
+}
+
+// convertInsomniaToWorkspace converts an Insomnia export to an HTTPie
+// workspace, so it goes through the same conversion path as HTTPie input.
+// Request groups become collections and sub-environments are merged over
+// the base environment. An export with several workspaces gets one
+// collection per workspace.
+func convertInsomniaToWorkspace(export *InsomniaExport, sourcePath string) HTTPieWorkspace {
+	c := &insomniaConverter{
+		children: map[string][]InsomniaResource{},
+		warned:   map[string]bool{},
+	}
+	var workspaces []InsomniaResource
+	for _, resource := range export.Resources {
+		if resource.Type == "workspace" {
+			workspaces = append(workspaces, resource)
+		}
+		c.children[resource.ParentID] = append(c.children[resource.ParentID], resource)
+	}
+	for parentID := range c.children {
+		children := c.children[parentID]
+		sort.SliceStable(children, func(i, j int) bool {
+			return children[i].MetaSortKey < children[j].MetaSortKey
+		})
+	}
+
+	workspace := HTTPieWorkspace{
+		Meta: HTTPieMeta{Format: "httpie", Version: "1.0.0", Source: "insomnia"},
+	}
+
+	switch len(workspaces) {
+	case 0:
+		log.Printf("Warning: no workspace found in Insomnia export %s", sourcePath)
+	case 1:
+		collection := c.convertGroup(workspaces[0])
+		workspace.Entry = HTTPieEntry{
+			Name:        collection.Name,
+			Auth:        collection.Auth,
+			Requests:    collection.Requests,
+			Collections: collection.Collections,
+		}
+		workspace.Environments = c.convertEnvironments(workspaces[0].ID)
+	default:
+		workspace.Entry = HTTPieEntry{Name: "Insomnia export", Auth: HTTPieAuth{Type: "none"}}
+		for _, resource := range workspaces {
+			workspace.Entry.Collections = append(workspace.Entry.Collections, c.convertGroup(resource))
+			workspace.Environments = mergeEnvironments(workspace.Environments, c.convertEnvironments(resource.ID))
+		}
+	}
+
+	return workspace
+}
+
+// convertGroup converts a workspace or request group and its children.
+func (c *insomniaConverter) convertGroup(group InsomniaResource) HTTPieCollection {
+	collection := HTTPieCollection{
+		Name:     group.Name,
+		Auth:     c.convertAuth(group.Authentication, nil),
+		Requests: []HTTPieRequest{},
+	}
+
+	for _, child := range c.children[group.ID] {
+		switch child.Type {
+		case "request":
+			collection.Requests = append(collection.Requests, c.convertRequest(child))
+		case "request_group":
+			collection.Collections = append(collection.Collections, c.convertGroup(child))
+		case "environment", "cookie_jar", "api_spec", "unit_test_suite", "proto_file":
+			// Not requests; environments are handled separately
+		default:
+			c.warn(child.Type, fmt.Sprintf("Warning: Insomnia %s resources are not supported, skipping %q", child.Type, child.Name))
+		}
+	}
+	return collection
+}
+
+func (c *insomniaConverter) convertRequest(resource InsomniaResource) HTTPieRequest {
+	req := HTTPieRequest{
+		Name:        resource.Name,
+		URL:         convertInsomniaTemplate(resource.URL),
+		Method:      strings.ToUpper(resource.Method),
+		Headers:     []HTTPieHeader{},
+		QueryParams: []HTTPieQueryParam{},
+		PathParams:  []HTTPiePathParam{},
+		Body:        HTTPieBody{Type: "none", Form: HTTPieForm{Fields: []HTTPieFormField{}}},
+	}
+	if req.Method == "" {
+		req.Method = "GET"
+	}
+
+	for _, header := range resource.Headers {
+		req.Headers = append(req.Headers, HTTPieHeader{
+			Name:    convertInsomniaTemplate(header.Name),
+			Value:   convertInsomniaTemplate(header.Value),
+			Enabled: !header.Disabled,
+		})
+	}
+	for _, param := range resource.Parameters {
+		req.QueryParams = append(req.QueryParams, HTTPieQueryParam{
+			Name:    convertInsomniaTemplate(param.Name),
+			Value:   convertInsomniaTemplate(param.Value),
+			Enabled: !param.Disabled,
+		})
+	}
+
+	req.Auth = c.convertAuth(resource.Authentication, &req)
+	req.Body = c.convertBody(resource.Body)
+	return req
+}
+
+// convertBody maps an Insomnia body, which is typed by its MIME type.
+func (c *insomniaConverter) convertBody(body InsomniaBody) HTTPieBody {
+	result := HTTPieBody{Type: "none", Form: HTTPieForm{Fields: []HTTPieFormField{}}}
+	mimeType := strings.ToLower(body.MimeType)
+
+	switch {
+	case mimeType == "":
+		if body.Text != "" {
+			result.Type = "text"
+			result.Text = HTTPieText{Value: convertInsomniaTemplate(body.Text)}
+		}
+	case mimeType == "application/x-www-form-urlencoded" || mimeType == "multipart/form-data":
+		result.Type = "form"
+		result.Form.IsMultipart = mimeType == "multipart/form-data"
+		for _, param := range body.Params {
+			field := HTTPieFormField{
+				Name:    convertInsomniaTemplate(param.Name),
+				Value:   convertInsomniaTemplate(param.Value),
+				Enabled: !param.Disabled,
+			}
+			if param.Type == "file" {
+				field.Type = "file"
+				field.Value = param.FileName
+			}
+			result.Form.Fields = append(result.Form.Fields, field)
+		}
+	case mimeType == "application/graphql":
+		// The text holds the JSON request: {"query": ..., "variables": ...}
+		var payload struct {
+			Query     string          `json:"query"`
+			Variables json.RawMessage `json:"variables"`
+		}
+		if err := json.Unmarshal([]byte(body.Text), &payload); err != nil {
+			payload.Query = body.Text
+		}
+		result.Type = "graphql"
+		result.GraphQL = HTTPieGraphQL{Query: convertInsomniaTemplate(payload.Query)}
+		if len(payload.Variables) > 0 && string(payload.Variables) != "null" {
+			result.GraphQL.Variables = convertInsomniaTemplate(string(payload.Variables))
+		}
+	case body.FileName != "":
+		result.Type = "file"
+		result.File.Name = body.FileName
+	default:
+		result.Type = "text"
+		result.Text = HTTPieText{Value: convertInsomniaTemplate(body.Text), Format: body.MimeType}
+	}
+	return result
+}
+
+// convertAuth maps Insomnia auth to HTTPie auth. An empty auth object
+// inherits from the parent. API keys sent as query parameters and bearer
+// tokens with a custom prefix are added to req directly, as HTTPie auth
+// cannot express them; req is nil for folder auth.
+func (c *insomniaConverter) convertAuth(auth InsomniaAuth, req *HTTPieRequest) HTTPieAuth {
+	if auth.Disabled {
+		return HTTPieAuth{Type: "none"}
+	}
+
+	switch auth.Type {
+	case "":
+		return HTTPieAuth{Type: "inherit"}
+	case "none":
+		return HTTPieAuth{Type: "none"}
+	case "basic":
+		return HTTPieAuth{Type: "basic", Credentials: HTTPieAuthCredentials{
+			Username: convertInsomniaTemplate(auth.Username),
+			Password: convertInsomniaTemplate(auth.Password),
+		}}
+	case "bearer":
+		token := convertInsomniaTemplate(auth.Token)
+		if auth.Prefix == "" || auth.Prefix == "Bearer" {
+			return HTTPieAuth{Type: "bearer", Credentials: HTTPieAuthCredentials{Password: token}}
+		}
+		if req != nil {
+			req.Headers = append(req.Headers, HTTPieHeader{Name: "Authorization", Value: auth.Prefix + " " + token, Enabled: true})
+			return HTTPieAuth{Type: "none"}
+		}
+	case "apikey":
+		key, value := convertInsomniaTemplate(auth.Key), convertInsomniaTemplate(auth.Value)
+		if auth.AddTo != "queryParams" {
+			return HTTPieAuth{Type: "apiKey", Credentials: HTTPieAuthCredentials{Username: key, Password: value}}
+		}
+		if req != nil {
+			req.QueryParams = append(req.QueryParams, HTTPieQueryParam{Name: key, Value: value, Enabled: true})
+			return HTTPieAuth{Type: "none"}
+		}
+	}
+
+	if req == nil && (auth.Type == "bearer" || auth.Type == "apikey") {
+		c.warn("folder-auth:"+auth.Type, fmt.Sprintf("Warning: Insomnia folder %s auth with a custom prefix or query parameter is not supported and was dropped", auth.Type))
+	} else {
+		c.warn("auth:"+auth.Type, fmt.Sprintf("Warning: Insomnia %q auth is not supported and was dropped", auth.Type))
+	}
+	return HTTPieAuth{Type: "none"}
+}
+
+// convertEnvironments returns the sub-environments of a workspace, each
+// merged over the base environment. With no sub-environments, the base
+// environment itself is returned.
+func (c *insomniaConverter) convertEnvironments(workspaceID string) []HTTPieEnvironment {
+	var environments []HTTPieEnvironment
+	for _, base := range c.children[workspaceID] {
+		if base.Type != "environment" {
+			continue
+		}
+		baseValues := flattenInsomniaData("", base.Data)
+
+		subEnvironments := 0
+		for _, sub := range c.children[base.ID] {
+			if sub.Type != "environment" {
+				continue
+			}
+			subEnvironments++
+			values := map[string]string{}
+			for key, value := range baseValues {
+				values[key] = value
+			}
+			for key, value := range flattenInsomniaData("", sub.Data) {
+				values[key] = value
+			}
+			environments = append(environments, insomniaEnvironment(sub, values, len(environments) == 0))
+		}
+		if subEnvironments == 0 && len(baseValues) > 0 {
+			environments = append(environments, insomniaEnvironment(base, baseValues, true))
+		}
+	}
+	return environments
+}
+
+func insomniaEnvironment(resource InsomniaResource, values map[string]string, isDefault bool) HTTPieEnvironment {
+	environment := HTTPieEnvironment{
+		Name:        resource.Name,
+		Color:       resource.Color,
+		IsDefault:   isDefault,
+		IsLocalOnly: resource.IsPrivate,
+		Variables:   []HTTPieEnvironmentVariable{},
+	}
+	for _, key := range sortedKeys(values) {
+		environment.Variables = append(environment.Variables, HTTPieEnvironmentVariable{Name: key, Value: values[key]})
+	}
+	return environment
+}
+
+// flattenInsomniaData turns nested environment data into dotted names
+// ({"api": {"host": "x"}} becomes api.host), matching {{ _.api.host }}.
+func flattenInsomniaData(prefix string, data map[string]any) map[string]string {
+	values := map[string]string{}
+	for key, value := range data {
+		name := key
+		if prefix != "" {
+			name = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			for nestedKey, nestedValue := range flattenInsomniaData(name, v) {
+				values[nestedKey] = nestedValue
+			}
+		case string:
+			values[name] = convertInsomniaTemplate(v)
+		case nil:
+			values[name] = ""
+		default:
+			encoded, _ := json.Marshal(v)
+			values[name] = string(encoded)
+		}
+	}
+	return values
+}
+
+func (c *insomniaConverter) warn(key, message string) {
+	if !c.warned[key] {
+		c.warned[key] = true
+		log.Print(message)
+	}
+}
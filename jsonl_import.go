@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/url"
+	"path/filepath"
+	"strings"
+)
+
+// JSON Lines request logs, one request per line, as dumped by services that
+// capture their own traffic:
+//
+//	{"method": "GET", "url": "https://api.example.com/users?page=2", "folder": "Users"}
+//	{"method": "POST", "url": "https://api.example.com/users", "headers": {"Content-Type": "application/json"}, "body": {"name": "Ann"}, "response": {"status": 201, "body": {"id": 7}}}
+//
+// Only url is required. A string body is sent as is; any other JSON value is
+// sent as JSON.
+type JSONLRequest struct {
+	Method   string            `json:"method"`
+	URL      string            `json:"url"`
+	Headers  map[string]string `json:"headers"`
+	Body     json.RawMessage   `json:"body"`
+	Name     string            `json:"name"`
+	Folder   string            `json:"folder"` // Slash-separated for nested folders
+	Response *JSONLResponse    `json:"response"`
+}
+
+type JSONLResponse struct {
+	Status     int               `json:"status"`
+	StatusText string            `json:"statusText"`
+	Headers    map[string]string `json:"headers"`
+	Body       json.RawMessage   `json:"body"`
+}
+
+// maxJSONLLineSize bounds a single logged request, bodies included.
+const maxJSONLLineSize = 64 * 1024 * 1024
+
+// isJSONLRequestLog reports whether data looks like a JSON Lines request
+// log: its first line is a JSON object with a url.
+func isJSONLRequestLog(data []byte) bool {
+	line, _, _ := bytes.Cut(bytes.TrimSpace(data), []byte("\n"))
+	var first struct {
+		URL string `json:"url"`
+	}
+	return json.Unmarshal(bytes.TrimSpace(line), &first) == nil && first.URL != ""
+}
+
+// convertJSONLToPostman builds a Postman collection from a request log, read
+// line by line. Requests go into the folder they name; the others are
+// grouped into one folder per host when they span several hosts. Repeated
+// requests (same folder, name, method, URL and body) become one request with
+// several saved responses, as for HAR files.
+func convertJSONLToPostman(data []byte, sourcePath string) PostmanCollection {
+	name := strings.TrimSuffix(filepath.Base(sourcePath), filepath.Ext(sourcePath))
+	if sourcePath == "-" || name == "" {
+		name = "Request log"
+	}
+
+	collection := PostmanCollection{
+		Info: PostmanInfo{
+			PostmanID:   generatePostmanID("jsonl", sourceNames([]string{sourcePath})[0], name),
+			Name:        name,
+			Description: "Converted from a JSON Lines request log",
+			Schema:      "https://schema.getpostman.com/json/collection/v2.1.0/collection.json",
+		},
+		Item: []PostmanItem{},
+	}
+
+	type logEntry struct {
+		folder []string
+		host   string
+		item   PostmanItem
+	}
+	var entries []logEntry
+	seen := map[string]int{}
+	hosts := map[string]bool{}
+	lines, skipped := 0, 0
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, maxJSONLLineSize)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		lines++
+
+		var logged JSONLRequest
+		if err := json.Unmarshal(line, &logged); err != nil {
+			log.Printf("Warning: skipping line %d of %s: %v", lineNumber, sourcePath, err)
+			skipped++
+			continue
+		}
+		u, err := url.Parse(logged.URL)
+		if err != nil || u.Host == "" {
+			log.Printf("Warning: skipping line %d of %s: invalid URL %q", lineNumber, sourcePath, logged.URL)
+			skipped++
+			continue
+		}
+
+		request := convertHARRequest(jsonlHARRequest(logged))
+		var folder []string
+		for _, part := range strings.Split(logged.Folder, "/") {
+			if part = strings.TrimSpace(part); part != "" {
+				folder = append(folder, part)
+			}
+		}
+
+		var response *PostmanResponse
+		if logged.Response != nil {
+			converted := convertHARResponse(jsonlHARResponse(*logged.Response), request)
+			response = &converted
+		}
+
+		bodyKey, _ := json.Marshal(request.Body)
+		key := strings.Join(folder, "/") + "\n" + logged.Name + "\n" + request.Method + " " + request.URL.Raw + "\n" + string(bodyKey)
+		if index, ok := seen[key]; ok {
+			if response != nil {
+				entries[index].item.Response = append(entries[index].item.Response, *response)
+			}
+			continue
+		}
+
+		itemName := logged.Name
+		if itemName == "" {
+			itemName = request.Method + " " + u.EscapedPath()
+			if u.EscapedPath() == "" {
+				itemName = request.Method + " /"
+			}
+		}
+		item := PostmanItem{Name: itemName, Request: request}
+		if response != nil {
+			item.Response = []PostmanResponse{*response}
+		}
+
+		if folder == nil {
+			hosts[u.Hostname()] = true
+		}
+		seen[key] = len(entries)
+		entries = append(entries, logEntry{folder: folder, host: u.Hostname(), item: item})
+	}
+	if err := scanner.Err(); err != nil {
+		log.Printf("Warning: stopped reading %s after %d lines: %v", sourcePath, lines, err)
+	}
+
+	for _, entry := range entries {
+		folder := entry.folder
+		if folder == nil && len(hosts) > 1 {
+			folder = []string{entry.host}
+		}
+		collection.Item = addToFolder(collection.Item, folder, entry.item)
+	}
+
+	if skipped > 0 {
+		log.Printf("Skipped %d of %d logged requests (invalid JSON or URLs)", skipped, lines)
+	}
+	return collection
+}
+
+// jsonlHARRequest maps a logged request to a HAR request, so it is converted
+// like a recorded one.
+func jsonlHARRequest(logged JSONLRequest) HARRequest {
+	harReq := HARRequest{Method: logged.Method, URL: logged.URL}
+	if harReq.Method == "" {
+		harReq.Method = "GET"
+	}
+
+	contentType := ""
+	for _, name := range sortedKeys(logged.Headers) {
+		if strings.EqualFold(name, "Content-Type") {
+			contentType = logged.Headers[name]
+		}
+		harReq.Headers = append(harReq.Headers, HARNameValue{Name: name, Value: logged.Headers[name]})
+	}
+
+	if text, isJSON := jsonlBody(logged.Body); text != "" {
+		if isJSON && contentType == "" {
+			contentType = "application/json"
+			harReq.Headers = append(harReq.Headers, HARNameValue{Name: "Content-Type", Value: contentType})
+		}
+		harReq.PostData = &HARPostData{MimeType: contentType, Text: text}
+	}
+	return harReq
+}
+
+// jsonlHARResponse maps a logged response to a HAR response.
+func jsonlHARResponse(logged JSONLResponse) HARResponse {
+	harResp := HARResponse{Status: logged.Status, StatusText: logged.StatusText}
+	for _, name := range sortedKeys(logged.Headers) {
+		if strings.EqualFold(name, "Content-Type") {
+			harResp.Content.MimeType = logged.Headers[name]
+		}
+		harResp.Headers = append(harResp.Headers, HARNameValue{Name: name, Value: logged.Headers[name]})
+	}
+
+	text, isJSON := jsonlBody(logged.Body)
+	if isJSON && harResp.Content.MimeType == "" {
+		harResp.Content.MimeType = "application/json"
+	}
+	harResp.Content.Text = text
+	return harResp
+}
+
+// jsonlBody returns a logged body as text, and whether it was a JSON value
+// rather than a string. JSON values are indented.
+func jsonlBody(raw json.RawMessage) (string, bool) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", false
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text, false
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, raw, "", "  "); err != nil {
+		return string(raw), true
+	}
+	return indented.String(), true
+}
+
+// addToFolder adds item under the folder path, creating folders as needed.
+func addToFolder(items []PostmanItem, folder []string, item PostmanItem) []PostmanItem {
+	if len(folder) == 0 {
+		return append(items, item)
+	}
+	for i := range items {
+		if items[i].Request == nil && items[i].Name == folder[0] {
+			items[i].Item = addToFolder(items[i].Item, folder[1:], item)
+			return items
+		}
+	}
+	return append(items, PostmanItem{Name: folder[0], Item: addToFolder(nil, folder[1:], item)})
+}
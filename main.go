@@ -2,12 +2,15 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 	"github.com/google/uuid"
@@ -21,10 +24,11 @@ type HTTPieWorkspace struct {
 }
 
 type HTTPieCollection struct {
-	Name     string          `json:"name"`
-	Icon     HTTPieIcon      `json:"icon"`
-	Auth     HTTPieAuth      `json:"auth"`
-	Requests []HTTPieRequest `json:"requests"`
+	Name        string             `json:"name"`
+	Icon        HTTPieIcon         `json:"icon"`
+	Auth        HTTPieAuth         `json:"auth"`
+	Requests    []HTTPieRequest    `json:"requests"`
+	Collections []HTTPieCollection `json:"collections,omitempty"` // Nested collections (sub-folders)
 }
 
 type HTTPieEnvironment struct {
@@ -98,9 +102,10 @@ type HTTPieQueryParam struct {
 }
 
 type HTTPiePathParam struct {
-	Name    string `json:"name"`
-	Value   string `json:"value"`
-	Enabled bool   `json:"enabled"`
+	Name        string `json:"name"`
+	Value       string `json:"value"`
+	Enabled     bool   `json:"enabled"`
+	Description string `json:"description,omitempty"`
 }
 
 type HTTPieBody struct {
@@ -129,6 +134,7 @@ type HTTPieFormField struct {
 	Name    string `json:"name"`
 	Value   string `json:"value"`
 	Enabled bool   `json:"enabled"`
+	Type    string `json:"type,omitempty"` // "text" (default) or "file" for multipart uploads
 }
 
 type HTTPieGraphQL struct {
@@ -140,6 +146,7 @@ type HTTPieGraphQL struct {
 type PostmanCollection struct {
 	Info     PostmanInfo       `json:"info"`
 	Item     []PostmanItem     `json:"item"`
+	Auth     *PostmanAuth      `json:"auth,omitempty"`
 	Variable []PostmanVariable `json:"variable,omitempty"`
 }
 
@@ -151,9 +158,22 @@ type PostmanInfo struct {
 }
 
 type PostmanItem struct {
-	Name    string         `json:"name"`
-	Request *PostmanRequest `json:"request,omitempty"`
-	Item    []PostmanItem  `json:"item,omitempty"`
+	Name     string            `json:"name"`
+	Request  *PostmanRequest   `json:"request,omitempty"`
+	Item     []PostmanItem     `json:"item,omitempty"`
+	Auth     *PostmanAuth      `json:"auth,omitempty"`     // Folder-level auth, inherited by child requests
+	Response []PostmanResponse `json:"response,omitempty"` // Saved example responses
+}
+
+// PostmanResponse is a saved example response of a request.
+type PostmanResponse struct {
+	Name            string          `json:"name"`
+	OriginalRequest *PostmanRequest `json:"originalRequest,omitempty"`
+	Status          string          `json:"status,omitempty"`
+	Code            int             `json:"code,omitempty"`
+	PreviewLanguage string          `json:"_postman_previewlanguage,omitempty"`
+	Header          []PostmanHeader `json:"header,omitempty"`
+	Body            string          `json:"body,omitempty"`
 }
 
 type PostmanRequest struct {
@@ -197,9 +217,37 @@ type PostmanHeader struct {
 }
 
 type PostmanBody struct {
-	Mode    string              `json:"mode"`
-	Raw     string              `json:"raw,omitempty"`
-	Options *PostmanBodyOptions `json:"options,omitempty"`
+	Mode       string                   `json:"mode"`
+	Raw        string                   `json:"raw,omitempty"`
+	URLEncoded []PostmanURLEncodedParam `json:"urlencoded,omitempty"`
+	FormData   []PostmanFormDataParam   `json:"formdata,omitempty"`
+	GraphQL    *PostmanGraphQL          `json:"graphql,omitempty"`
+	File       *PostmanBodyFile         `json:"file,omitempty"`
+	Options    *PostmanBodyOptions      `json:"options,omitempty"`
+}
+
+type PostmanBodyFile struct {
+	Src string `json:"src"`
+}
+
+type PostmanGraphQL struct {
+	Query     string `json:"query"`
+	Variables string `json:"variables,omitempty"`
+}
+
+type PostmanURLEncodedParam struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Type     string `json:"type,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+type PostmanFormDataParam struct {
+	Key      string `json:"key"`
+	Value    string `json:"value,omitempty"`
+	Src      string `json:"src,omitempty"`
+	Type     string `json:"type"`
+	Disabled bool   `json:"disabled,omitempty"`
 }
 
 type PostmanBodyOptions struct {
@@ -211,15 +259,42 @@ type PostmanBodyRaw struct {
 }
 
 type PostmanURL struct {
-	Raw   string              `json:"raw"`
-	Host  []string            `json:"host,omitempty"`
-	Path  []string            `json:"path,omitempty"`
-	Query []PostmanQueryParam `json:"query,omitempty"`
+	Raw      string               `json:"raw"`
+	Protocol string               `json:"protocol,omitempty"`
+	Host     []string             `json:"host,omitempty"`
+	Port     string               `json:"port,omitempty"`
+	Path     []string             `json:"path,omitempty"`
+	Query    []PostmanQueryParam  `json:"query,omitempty"`
+	Hash     string               `json:"hash,omitempty"`
+	Variable []PostmanURLVariable `json:"variable,omitempty"`
+}
+
+// PostmanURLVariable is a path variable, referenced as :key in the URL path.
+type PostmanURLVariable struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
 }
 
 type PostmanQueryParam struct {
-	Key   string `json:"key"`
-	Value string `json:"value"`
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+// Postman environment file structure
+type PostmanEnvironment struct {
+	ID     string                    `json:"id"`
+	Name   string                    `json:"name"`
+	Values []PostmanEnvironmentValue `json:"values"`
+	Scope  string                    `json:"_postman_variable_scope"`
+}
+
+type PostmanEnvironmentValue struct {
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
 }
 
 type PostmanVariable struct {
@@ -229,6 +304,72 @@ type PostmanVariable struct {
 	Type  string `json:"type"`
 }
 
+// ConversionOptions holds settings shared by the convert and merge commands.
+type ConversionOptions struct {
+	// FileBaseDir, when set, makes file body paths relative to this directory.
+	FileBaseDir string
+	// EnvironmentDir, when set, exports each HTTPie environment as a Postman
+	// environment file in this directory, and collection variables only
+	// cover names the requests reference.
+	EnvironmentDir string
+	// SecretMode controls how secret environment variables are written to
+	// collection variables: "keep", "blank" or "separate".
+	SecretMode string
+	// Deterministic replaces time-based and random IDs with IDs derived from
+	// the input, so identical inputs give byte-identical output.
+	Deterministic bool
+	// HARDomains, HARMimeTypes and HARMethods are comma-separated filters for
+	// entries imported from HAR files; empty keeps everything.
+	HARDomains   string
+	HARMimeTypes string
+	HARMethods   string
+	// HARKeepStatic keeps images, stylesheets, scripts, fonts and media
+	// recorded in HAR files, which are dropped by default.
+	HARKeepStatic bool
+	// ThunderEnvironments lists comma-separated Thunder Client environment
+	// exports to attach to Thunder Client collection inputs.
+	ThunderEnvironments string
+}
+
+var options ConversionOptions
+
+func registerConversionFlags(fs *flag.FlagSet) {
+	fs.StringVar(&options.FileBaseDir, "base-dir", "", "rewrite file body paths relative to this directory")
+	fs.StringVar(&options.EnvironmentDir, "env-dir", "", "write one Postman environment file per HTTPie environment into this directory")
+	fs.StringVar(&options.SecretMode, "secrets", "keep", "how to write secret variables into the collection: keep, blank or separate")
+	fs.BoolVar(&options.Deterministic, "deterministic", false, "derive IDs from the input so identical inputs give identical output")
+	fs.StringVar(&options.HARDomains, "har-domain", "", "only import HAR entries for these comma-separated domains (subdomains included)")
+	fs.StringVar(&options.HARMimeTypes, "har-mime", "", "only import HAR entries whose response MIME type contains one of these comma-separated values")
+	fs.StringVar(&options.HARMethods, "har-method", "", "only import HAR entries with these comma-separated HTTP methods")
+	fs.BoolVar(&options.HARKeepStatic, "har-keep-static", false, "keep static assets (images, CSS, scripts, fonts, media) from HAR files")
+	fs.StringVar(&options.ThunderEnvironments, "thunder-env", "", "comma-separated Thunder Client environment exports for Thunder Client collections")
+}
+
+func validateOptions() {
+	switch options.SecretMode {
+	case "keep", "blank", "separate":
+	default:
+		log.Fatalf("Invalid -secrets value %q: expected keep, blank or separate", options.SecretMode)
+	}
+}
+
+// parseArgs parses flags from args, allowing them to appear before, between
+// or after positional arguments, and returns the positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			os.Exit(2)
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
 func main() {
 	if len(os.Args) < 2 {
 		printUsage()
@@ -238,45 +379,185 @@ func main() {
 	switch os.Args[1] {
 	case "merge":
 		handleMergeCommand()
+	case "to-httpie":
+		handleToHTTPieCommand()
+	case "to-openapi":
+		handleToOpenAPICommand()
+	case "to-curl":
+		handleToCurlCommand()
+	case "to-har":
+		handleToHARCommand()
+	case "to-bruno":
+		handleToBrunoCommand()
+	case "to-http":
+		handleToHTTPFileCommand()
+	case "to-httpie-cli":
+		handleToHTTPieCLICommand()
 	default:
 		handleConvertCommand()
 	}
 }
 
 func handleMergeCommand() {
-	if len(os.Args) < 4 {
-		fmt.Println("Usage: postmanzier merge <output-file> <input-file-1> [<input-file-2> ...]")
+	fs := flag.NewFlagSet("merge", flag.ExitOnError)
+	registerConversionFlags(fs)
+	args := parseArgs(fs, os.Args[2:])
+	validateOptions()
+
+	if len(args) < 2 {
+		fmt.Println("Usage: postmanzier merge [options] <output-file> <input-file-1> [<input-file-2> ...]")
 		fmt.Println("Example: postmanzier merge merged.postman.json collection1.json collection2.json")
 		os.Exit(1)
 	}
 
-	outputFile := os.Args[2]
-	inputFiles := os.Args[3:]
+	outputFile := args[0]
+	inputFiles := args[1:]
 
-	firstFile, err := os.ReadFile(inputFiles[0])
-	if err != nil {
-		log.Fatalf("Error reading first input file %s: %v", inputFiles[0], err)
+	// HTTPie-only merges are merged as workspaces; anything else is loaded
+	// per file and merged as Postman collections
+	allHTTPie := true
+	for _, inputFile := range inputFiles {
+		data, err := readInput(inputFile)
+		if err != nil {
+			log.Fatalf("Error reading input file %s: %v", inputFile, err)
+		}
+		if !isWorkspaceFormat(detectInputFormat(data)) {
+			allHTTPie = false
+			break
+		}
 	}
 
-	if isPostmanCollection(firstFile) {
-		mergePostmanCollections(outputFile, inputFiles)
-	} else {
+	if allHTTPie {
 		mergeHTTPieCollections(outputFile, inputFiles)
+	} else {
+		mergePostmanCollections(outputFile, inputFiles)
 	}
 }
 
+// isPostmanCollection reports whether data is a Postman collection of any
+// schema version (see postmanCollectionVersion).
 func isPostmanCollection(data []byte) bool {
-	var collection PostmanCollection
-	if err := json.Unmarshal(data, &collection); err != nil {
-		return false
+	return postmanCollectionVersion(data) != ""
+}
+
+// detectInputFormat reports the format of an input file: "postman",
+// "openapi", "har", "insomnia", "thunder", "thunder-environment", "jsonl",
+// "curl", "http-file" or "httpie" (the default).
+func detectInputFormat(data []byte) string {
+	switch {
+	case isPostmanCollection(data):
+		return "postman"
+	case parseInsomniaExport(data) != nil:
+		return "insomnia"
+	case parseThunderCollection(data) != nil:
+		return "thunder"
+	case parseThunderEnvironment(data) != nil:
+		return "thunder-environment"
+	case parseHARFile(data) != nil:
+		return "har"
+	case parseOpenAPIDocument(data) != nil:
+		return "openapi"
+	case isJSONLRequestLog(data):
+		return "jsonl"
+	case isCurlScript(data):
+		return "curl"
+	case isHTTPFile(data):
+		return "http-file"
+	default:
+		return "httpie"
 	}
-	return collection.Info.Schema != ""
+}
+
+// loadCollection reads a collection file in any supported input format and
+// returns it as a Postman collection.
+func loadCollection(path string) (PostmanCollection, error) {
+	collection, _, err := loadCollectionWithEnvironments(path)
+	return collection, err
+}
+
+// loadCollectionWithEnvironments is loadCollection that also returns the
+// environments of workspace-format inputs (see isWorkspaceFormat).
+func loadCollectionWithEnvironments(path string) (PostmanCollection, []HTTPieEnvironment, error) {
+	data, err := readInput(path)
+	if err != nil {
+		return PostmanCollection{}, nil, err
+	}
+
+	switch detectInputFormat(data) {
+	case "postman":
+		collection, err := parsePostmanCollection(data)
+		return collection, nil, err
+	case "openapi":
+		return convertOpenAPIToPostman(parseOpenAPIDocument(data), path), nil, nil
+	case "har":
+		return convertHARToPostman(parseHARFile(data), path), nil, nil
+	case "jsonl":
+		return convertJSONLToPostman(data, path), nil, nil
+	case "curl":
+		return convertWorkspaceToPostman(convertCurlToWorkspace(data, path), path), nil, nil
+	case "http-file":
+		return convertWorkspaceToPostman(convertHTTPFileToWorkspace(data, path), path), nil, nil
+	case "thunder-environment":
+		return PostmanCollection{}, nil, fmt.Errorf("%s is a Thunder Client environment export; pass it with -thunder-env", path)
+	}
+
+	httpieWorkspace, err := parseWorkspace(data, path)
+	if err != nil {
+		return PostmanCollection{}, nil, err
+	}
+	return convertWorkspaceToPostman(httpieWorkspace, path), httpieWorkspace.Environments, nil
+}
+
+// isWorkspaceFormat reports whether inputs of this format are converted
+// through an HTTPie workspace, keeping their environments.
+func isWorkspaceFormat(format string) bool {
+	return format == "httpie" || format == "insomnia" || format == "thunder"
+}
+
+// parseWorkspace parses an HTTPie workspace, or converts an Insomnia export
+// or a Thunder Client collection to one.
+func parseWorkspace(data []byte, path string) (HTTPieWorkspace, error) {
+	if export := parseInsomniaExport(data); export != nil {
+		return convertInsomniaToWorkspace(export, path), nil
+	}
+	if collection := parseThunderCollection(data); collection != nil {
+		environments, err := loadThunderEnvironments(options.ThunderEnvironments)
+		if err != nil {
+			return HTTPieWorkspace{}, err
+		}
+		return convertThunderToWorkspace(collection, environments), nil
+	}
+
+	var httpieWorkspace HTTPieWorkspace
+	if err := json.Unmarshal(data, &httpieWorkspace); err != nil {
+		return HTTPieWorkspace{}, fmt.Errorf("parsing HTTPie collection: %w", err)
+	}
+	return httpieWorkspace, nil
+}
+
+// stdinData caches standard input so "-" can be read more than once (format
+// detection, then loading).
+var stdinData []byte
+
+// readInput reads an input file, or standard input when path is "-".
+func readInput(path string) ([]byte, error) {
+	if path != "-" {
+		return os.ReadFile(path)
+	}
+	if stdinData == nil {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		stdinData = data
+	}
+	return stdinData, nil
 }
 
 func mergePostmanCollections(outputFile string, inputFiles []string) {
 	mergedCollection := PostmanCollection{
 		Info: PostmanInfo{
-			PostmanID:   generatePostmanID(),
+			PostmanID:   generatePostmanID(append([]string{"merge-postman"}, sourceNames(inputFiles)...)...),
 			Name:        "Merged Postman Collections",
 			Description: "Merged from multiple Postman collections",
 			Schema:      "https://schema.getpostman.com/json/collection/v2.1.0/collection.json",
@@ -286,19 +567,15 @@ func mergePostmanCollections(outputFile string, inputFiles []string) {
 	}
 
 	allVariables := make(map[string]PostmanVariable)
+	var allEnvironments []HTTPieEnvironment
 
 	for _, inputFile := range inputFiles {
-		data, err := os.ReadFile(inputFile)
+		postmanCollection, environments, err := loadCollectionWithEnvironments(inputFile)
 		if err != nil {
 			log.Printf("Error reading input file %s: %v. Skipping.", inputFile, err)
 			continue
 		}
-
-		var postmanCollection PostmanCollection
-		if err := json.Unmarshal(data, &postmanCollection); err != nil {
-			log.Printf("Error parsing Postman collection from %s: %v. Skipping.", inputFile, err)
-			continue
-		}
+		allEnvironments = mergeEnvironments(allEnvironments, environments)
 
 		folderName := postmanCollection.Info.Name
 		if folderName == "" {
@@ -308,6 +585,7 @@ func mergePostmanCollections(outputFile string, inputFiles []string) {
 		folder := PostmanItem{
 			Name: folderName,
 			Item: postmanCollection.Item,
+			Auth: postmanCollection.Auth,
 		}
 
 		mergedCollection.Item = append(mergedCollection.Item, folder)
@@ -320,9 +598,19 @@ func mergePostmanCollections(outputFile string, inputFiles []string) {
 		}
 	}
 
+	// Secret variables of workspace inputs and of Postman collections are
+	// handled like in an HTTPie-only merge
+	secrets := secretVariableNames(allEnvironments)
 	for _, v := range allVariables {
+		if v.Type == "secret" {
+			secrets[v.Key] = true
+		}
 		mergedCollection.Variable = append(mergedCollection.Variable, v)
 	}
+	sortVariables(mergedCollection.Variable)
+
+	var secretVariables []PostmanVariable
+	mergedCollection.Variable, secretVariables = applySecretMode(mergedCollection.Variable, secrets)
 
 	// Write merged Postman collection
 	outputData, err := json.MarshalIndent(mergedCollection, "", "  ")
@@ -337,12 +625,20 @@ func mergePostmanCollections(outputFile string, inputFiles []string) {
 
 	fmt.Println("Postman collections merge completed!")
 	fmt.Printf("--> Output file: %s\n", finalOutputPath)
+
+	if len(secretVariables) > 0 {
+		fmt.Printf("--> Secrets file (do not commit): %s\n", writeSecretsFile(finalOutputPath, mergedCollection.Info.Name, secretVariables))
+	}
+
+	if options.EnvironmentDir != "" {
+		printEnvironmentFiles(exportEnvironments(allEnvironments, options.EnvironmentDir))
+	}
 }
 
 func mergeHTTPieCollections(outputFile string, inputFiles []string) {
 	mergedCollection := PostmanCollection{
 		Info: PostmanInfo{
-			PostmanID:   generatePostmanID(),
+			PostmanID:   generatePostmanID(append([]string{"merge-httpie"}, sourceNames(inputFiles)...)...),
 			Name:        "Merged HTTPie Collections",
 			Description: "Merged from multiple HTTPie collections",
 			Schema:      "https://schema.getpostman.com/json/collection/v2.1.0/collection.json",
@@ -352,17 +648,18 @@ func mergeHTTPieCollections(outputFile string, inputFiles []string) {
 	}
 
 	allVariables := make(map[string]string)
+	var allEnvironments []HTTPieEnvironment
 
 	for _, inputFile := range inputFiles {
-		data, err := os.ReadFile(inputFile)
+		data, err := readInput(inputFile)
 		if err != nil {
 			log.Printf("Error reading input file %s: %v. Skipping.", inputFile, err)
 			continue
 		}
 
-		var httpieWorkspace HTTPieWorkspace
-		if err := json.Unmarshal(data, &httpieWorkspace); err != nil {
-			log.Printf("Error parsing HTTPie collection from %s: %v. Skipping.", inputFile, err)
+		httpieWorkspace, err := parseWorkspace(data, inputFile)
+		if err != nil {
+			log.Printf("Error parsing %s: %v. Skipping.", inputFile, err)
 			continue
 		}
 
@@ -373,25 +670,16 @@ func mergeHTTPieCollections(outputFile string, inputFiles []string) {
 
 		folder := PostmanItem{
 			Name: folderName,
-			Item: []PostmanItem{},
-		}
-
-		// Convert direct requests
-		for _, req := range httpieWorkspace.Entry.Requests {
-			folder.Item = append(folder.Item, convertRequest(req))
-		}
-
-		// Convert requests from collections
-		for _, collection := range httpieWorkspace.Entry.Collections {
-			for _, req := range collection.Requests {
-				folder.Item = append(folder.Item, convertRequest(req))
-			}
+			Item: convertEntryItems(httpieWorkspace.Entry),
+			Auth: convertAuth(httpieWorkspace.Entry.Auth),
 		}
 
 		if len(folder.Item) > 0 {
 			mergedCollection.Item = append(mergedCollection.Item, folder)
 		}
 
+		allEnvironments = mergeEnvironments(allEnvironments, httpieWorkspace.Environments)
+
 		// Extract and merge variables
 		vars := extractVariablesFromWorkspace(httpieWorkspace)
 		for _, v := range vars {
@@ -401,14 +689,23 @@ func mergeHTTPieCollections(outputFile string, inputFiles []string) {
 		}
 	}
 
+	secrets := secretVariableNames(allEnvironments)
 	for key, value := range allVariables {
+		varType := "string"
+		if secrets[key] {
+			varType = "secret"
+		}
 		mergedCollection.Variable = append(mergedCollection.Variable, PostmanVariable{
-			ID:    uuid.New().String(),
+			ID:    newID("variable", key),
 			Key:   key,
 			Value: value,
-			Type:  "string",
+			Type:  varType,
 		})
 	}
+	sortVariables(mergedCollection.Variable)
+
+	var secretVariables []PostmanVariable
+	mergedCollection.Variable, secretVariables = applySecretMode(mergedCollection.Variable, secrets)
 
 	// Write merged Postman collection
 	outputData, err := json.MarshalIndent(mergedCollection, "", "  ")
@@ -423,31 +720,51 @@ func mergeHTTPieCollections(outputFile string, inputFiles []string) {
 
 	fmt.Println("HTTPie collections merge completed!")
 	fmt.Printf("--> Output file: %s\n", finalOutputPath)
+
+	if len(secretVariables) > 0 {
+		fmt.Printf("--> Secrets file (do not commit): %s\n", writeSecretsFile(finalOutputPath, mergedCollection.Info.Name, secretVariables))
+	}
+
+	if options.EnvironmentDir != "" {
+		printEnvironmentFiles(exportEnvironments(allEnvironments, options.EnvironmentDir))
+	}
 }
 
 func handleConvertCommand() {
-	if len(os.Args) < 3 {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	registerConversionFlags(fs)
+	args := parseArgs(fs, os.Args[1:])
+	validateOptions()
+
+	if len(args) < 2 {
 		printUsage()
 		os.Exit(1)
 	}
 
-	inputFile := os.Args[1]
-	outputPath := os.Args[2]
+	inputFile := args[0]
+	outputPath := args[1]
 
 	// Read HTTPie collection
-	data, err := os.ReadFile(inputFile)
+	data, err := readInput(inputFile)
 	if err != nil {
 		log.Fatalf("Error reading input file: %v", err)
 	}
 
-	// Try to parse as workspace format first (newer format)
-	var httpieWorkspace HTTPieWorkspace
-	if err := json.Unmarshal(data, &httpieWorkspace); err != nil {
-		log.Fatalf("Error parsing HTTPie collection: %v", err)
+	if format := detectInputFormat(data); !isWorkspaceFormat(format) {
+		convertCollectionFile(inputFile, outputPath)
+		return
+	}
+
+	httpieWorkspace, err := parseWorkspace(data, inputFile)
+	if err != nil {
+		log.Fatalf("Error parsing input collection: %v", err)
 	}
 
 	// Convert to Postman collection
-	postmanCollection := convertWorkspaceToPostman(httpieWorkspace)
+	postmanCollection := convertWorkspaceToPostman(httpieWorkspace, inputFile)
+
+	var secretVariables []PostmanVariable
+	postmanCollection.Variable, secretVariables = applySecretMode(postmanCollection.Variable, secretVariableNames(httpieWorkspace.Environments))
 
 	// Generate unique output filename if file exists
 	finalOutputPath := generateUniqueFilename(outputPath)
@@ -464,7 +781,7 @@ func handleConvertCommand() {
 
 	// Print results
 	totalInputAPIs := countTotalRequests(httpieWorkspace)
-	convertedAPIs := len(postmanCollection.Item)
+	convertedAPIs := countPostmanRequests(postmanCollection.Item)
 	totalVariables := len(postmanCollection.Variable)
 
 	errorStr := "\n"
@@ -477,65 +794,368 @@ func handleConvertCommand() {
 	fmt.Printf("* Total problematic APIs: %d\n", totalInputAPIs - convertedAPIs)
 	fmt.Printf("* Total variables: %d\n", totalVariables)
 	fmt.Printf("--> Output file: %s\n", finalOutputPath)
+
+	if len(secretVariables) > 0 {
+		fmt.Printf("--> Secrets file (do not commit): %s\n", writeSecretsFile(finalOutputPath, postmanCollection.Info.Name, secretVariables))
+	}
+
+	if options.EnvironmentDir != "" {
+		printEnvironmentFiles(exportEnvironments(httpieWorkspace.Environments, options.EnvironmentDir))
+	}
+}
+
+// convertCollectionFile converts a non-HTTPie input (see detectInputFormat)
+// to a Postman v2.1 collection.
+func convertCollectionFile(inputFile string, outputPath string) {
+	postmanCollection, err := loadCollection(inputFile)
+	if err != nil {
+		log.Fatalf("Error reading input collection: %v", err)
+	}
+
+	outputData, err := json.MarshalIndent(postmanCollection, "", "  ")
+	if err != nil {
+		log.Fatalf("Error marshaling Postman collection: %v", err)
+	}
+
+	finalOutputPath := generateUniqueFilename(outputPath)
+	if err := os.WriteFile(finalOutputPath, outputData, 0644); err != nil {
+		log.Fatalf("Error writing output file: %v", err)
+	}
+
+	fmt.Println("Migration completed!")
+	fmt.Printf("* Total APIs: %d\n", countPostmanRequests(postmanCollection.Item))
+	fmt.Printf("* Total variables: %d\n", len(postmanCollection.Variable))
+	fmt.Printf("--> Output file: %s\n", finalOutputPath)
+}
+
+// secretVariableNames returns the names of variables marked secret in any
+// of the environments.
+func secretVariableNames(environments []HTTPieEnvironment) map[string]bool {
+	secrets := make(map[string]bool)
+	for _, env := range environments {
+		for _, v := range env.Variables {
+			if v.IsSecret {
+				secrets[v.Name] = true
+			}
+		}
+	}
+	return secrets
+}
+
+// applySecretMode blanks secret collection variables according to
+// options.SecretMode. In "separate" mode the original secret values are
+// returned so they can be written to their own file.
+func applySecretMode(variables []PostmanVariable, secrets map[string]bool) ([]PostmanVariable, []PostmanVariable) {
+	if options.SecretMode == "keep" || len(secrets) == 0 {
+		return variables, nil
+	}
+
+	var secretVariables []PostmanVariable
+	for i, v := range variables {
+		if !secrets[v.Key] {
+			continue
+		}
+		if options.SecretMode == "separate" {
+			secretVariables = append(secretVariables, v)
+		}
+		variables[i].Value = ""
+	}
+	return variables, secretVariables
+}
+
+// writeSecretsFile writes secret variables as a Postman environment next to
+// the collection output and returns its path.
+func writeSecretsFile(collectionPath string, collectionName string, secretVariables []PostmanVariable) string {
+	env := PostmanEnvironment{
+		ID:     newID("secrets", collectionName),
+		Name:   strings.TrimSpace(collectionName + " Secrets"),
+		Values: []PostmanEnvironmentValue{},
+		Scope:  "environment",
+	}
+	for _, v := range secretVariables {
+		env.Values = append(env.Values, PostmanEnvironmentValue{
+			Key:     v.Key,
+			Value:   v.Value,
+			Type:    "secret",
+			Enabled: true,
+		})
+	}
+
+	outputData, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		log.Fatalf("Error marshaling secrets file: %v", err)
+	}
+
+	base := strings.TrimSuffix(collectionPath, filepath.Ext(collectionPath))
+	base = strings.TrimSuffix(base, ".postman")
+	path := generateUniqueFilename(base + ".secrets.postman_environment.json")
+	if err := os.WriteFile(path, outputData, 0600); err != nil {
+		log.Fatalf("Error writing secrets file: %v", err)
+	}
+	return path
+}
+
+// mergeEnvironments adds environments to the list, combining environments
+// with the same name. Existing variable values win over later ones.
+func mergeEnvironments(existing []HTTPieEnvironment, environments []HTTPieEnvironment) []HTTPieEnvironment {
+	for _, env := range environments {
+		index := -1
+		for i := range existing {
+			if existing[i].Name == env.Name {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			env.Variables = append([]HTTPieEnvironmentVariable(nil), env.Variables...)
+			existing = append(existing, env)
+			continue
+		}
+
+		for _, v := range env.Variables {
+			found := false
+			for _, ev := range existing[index].Variables {
+				if ev.Name == v.Name {
+					found = true
+					break
+				}
+			}
+			if !found {
+				existing[index].Variables = append(existing[index].Variables, v)
+			}
+		}
+	}
+	return existing
+}
+
+// convertEnvironment converts an HTTPie environment to a Postman environment.
+func convertEnvironment(env HTTPieEnvironment) PostmanEnvironment {
+	postmanEnv := PostmanEnvironment{
+		ID:     newID("environment", env.Name),
+		Name:   env.Name,
+		Values: []PostmanEnvironmentValue{},
+		Scope:  "environment",
+	}
+
+	for _, v := range env.Variables {
+		valueType := "default"
+		if v.IsSecret {
+			valueType = "secret"
+		}
+		postmanEnv.Values = append(postmanEnv.Values, PostmanEnvironmentValue{
+			Key:     v.Name,
+			Value:   v.Value,
+			Type:    valueType,
+			Enabled: true,
+		})
+	}
+
+	return postmanEnv
+}
+
+// exportEnvironments writes each environment as <name>.postman_environment.json
+// in dir and returns the paths written.
+func exportEnvironments(environments []HTTPieEnvironment, dir string) []string {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Fatalf("Error creating environment directory: %v", err)
+	}
+
+	var paths []string
+	for _, env := range environments {
+		outputData, err := json.MarshalIndent(convertEnvironment(env), "", "  ")
+		if err != nil {
+			log.Fatalf("Error marshaling Postman environment %s: %v", env.Name, err)
+		}
+
+		name := sanitizeFilename(env.Name)
+		if name == "" {
+			name = "environment"
+		}
+
+		path := generateUniqueFilename(filepath.Join(dir, name+".postman_environment.json"))
+		if err := os.WriteFile(path, outputData, 0644); err != nil {
+			log.Fatalf("Error writing environment file: %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+func printEnvironmentFiles(paths []string) {
+	fmt.Printf("* Total environments: %d\n", len(paths))
+	for _, path := range paths {
+		fmt.Printf("--> Environment file: %s\n", path)
+	}
+}
+
+// sanitizeFilename replaces characters that are unsafe in file names.
+func sanitizeFilename(name string) string {
+	return strings.TrimSpace(regexp.MustCompile(`[<>:"/\\|?*\x00-\x1f]`).ReplaceAllString(name, "_"))
 }
 
 func printUsage() {
-	fmt.Println("Usage: postmanzier <command> [arguments]")
+	fmt.Println("Usage: postmanzier <command> [options] [arguments]")
 	fmt.Println("\nCommands:")
-	fmt.Println("  <input-httpie-collection> <output-postman-collection>")
-	fmt.Println("    Converts a single HTTPie collection to a Postman collection.")
+	fmt.Println("  <input-collection> <output-postman-collection>")
+	fmt.Println("    Converts a single HTTPie collection, Insomnia v4 export, OpenAPI 3.x/Swagger 2.0 spec (JSON or YAML), HAR 1.2 file, .http file or script of curl commands to a Postman collection. Use - to read standard input.")
 	fmt.Println("    Example: postmanzier collection.json output.postman.json")
 	fmt.Println("\n  merge <output-file> <input-file-1> [<input-file-2> ...]")
-	fmt.Println("    Merges multiple HTTPie collections into a single Postman collection.")
+	fmt.Println("    Merges multiple HTTPie/Insomnia/Postman collections, OpenAPI specs, HAR files, .http files or curl scripts into a single Postman collection.")
 	fmt.Println("    Example: postmanzier merge merged.postman.json collection1.json collection2.json")
+	fmt.Println("\n  to-httpie <input-postman-collection> <output-httpie-collection> [<postman-environment> ...]")
+	fmt.Println("    Converts a Postman collection (and optional environments) back to an HTTPie workspace.")
+	fmt.Println("    Example: postmanzier to-httpie output.postman.json collection.json")
+	fmt.Println("\n  to-openapi <input-collection> <output-openapi-file>")
+	fmt.Println("    Exports an HTTPie or Postman collection as an OpenAPI 3.1 document (JSON).")
+	fmt.Println("    Example: postmanzier to-openapi collection.json openapi.json")
+	fmt.Println("\n  to-curl [-resolve] [-env <environment>] [-split] <input-collection> <output-script-or-dir>")
+	fmt.Println("    Exports every request as a curl command in a shell script (one script per folder with -split).")
+	fmt.Println("    Example: postmanzier to-curl -env Production collection.json requests.sh")
+	fmt.Println("\n  to-har [-resolve] [-env <environment>] [-deterministic] <input-collection> <output-har-file>")
+	fmt.Println("    Exports the request side of every item as HAR 1.2 entries.")
+	fmt.Println("    Example: postmanzier to-har -env Production collection.json requests.har")
+	fmt.Println("\n  to-bruno <input-collection> <output-dir> [<postman-environment> ...]")
+	fmt.Println("    Exports a collection as a Bruno collection directory with one .bru file per request.")
+	fmt.Println("    Example: postmanzier to-bruno collection.json bruno/")
+	fmt.Println("\n  to-http <input-collection> <output-http-file>")
+	fmt.Println("    Exports a collection as a .http file for the VS Code REST Client or JetBrains HTTP Client.")
+	fmt.Println("    Example: postmanzier to-http collection.json requests.http")
+	fmt.Println("\n  to-httpie-cli [-env <environment>] <input-collection> <output-dir>")
+	fmt.Println("    Exports each collection as a shell script of HTTPie http/https commands for smoke testing.")
+	fmt.Println("    Example: postmanzier to-httpie-cli -env Staging collection.json smoke/")
+	fmt.Println("\nOptions:")
+	fmt.Println("  -base-dir <dir>")
+	fmt.Println("    Rewrite file body paths relative to <dir> (HTTPie stores absolute paths).")
+	fmt.Println("  -env-dir <dir>")
+	fmt.Println("    Write each HTTPie environment to <dir> as a Postman environment file.")
+	fmt.Println("  -secrets keep|blank|separate")
+	fmt.Println("    How secret variables are written to the collection (default: keep).")
+	fmt.Println("    'separate' blanks them and writes their values to a .secrets.postman_environment.json file.")
+	fmt.Println("  -deterministic")
+	fmt.Println("    Derive IDs from the input so identical inputs give byte-identical output.")
+	fmt.Println("  -har-domain <list>, -har-mime <list>, -har-method <list>")
+	fmt.Println("    Only import HAR entries matching these comma-separated domains, response MIME types or methods.")
+	fmt.Println("  -har-keep-static")
+	fmt.Println("    Keep images, stylesheets, scripts, fonts and media recorded in HAR files (dropped by default).")
+	fmt.Println("  -thunder-env <list>")
+	fmt.Println("    Attach these comma-separated Thunder Client environment exports to Thunder Client collections.")
 }
 
-func convertWorkspaceToPostman(httpie HTTPieWorkspace) PostmanCollection {
+// convertWorkspaceToPostman converts a whole workspace. sourcePath is the
+// input file, used to derive stable IDs in deterministic mode.
+func convertWorkspaceToPostman(httpie HTTPieWorkspace, sourcePath string) PostmanCollection {
 	postman := PostmanCollection{
 		Info: PostmanInfo{
-			PostmanID:   generatePostmanID(),
+			PostmanID:   generatePostmanID("workspace", filepath.Base(sourcePath), httpie.Entry.Name),
 			Name:        httpie.Entry.Name,
 			Description: "Converted from HTTPie workspace",
 			Schema:      "https://schema.getpostman.com/json/collection/v2.1.0/collection.json",
 		},
-		Item:     []PostmanItem{},
+		Item:     convertEntryItems(httpie.Entry),
+		Auth:     convertAuth(httpie.Entry.Auth),
 		Variable: extractVariablesFromWorkspace(httpie),
 	}
 
-	// Create a folder for the collection content
+	return postman
+}
+
+// convertEntryItems converts the workspace entry into Postman items: direct
+// requests stay at the top level and every collection becomes a folder.
+// The entry's own auth is not included; callers attach it to the collection
+// or folder that holds these items.
+func convertEntryItems(entry HTTPieEntry) []PostmanItem {
+	items := []PostmanItem{}
+	hasAuth := convertAuth(entry.Auth) != nil
+
+	for _, req := range entry.Requests {
+		items = append(items, convertRequest(req, hasAuth))
+	}
+
+	for _, collection := range entry.Collections {
+		items = append(items, convertCollection(collection, hasAuth))
+	}
+
+	return items
+}
+
+// convertCollection converts an HTTPie collection into a Postman folder,
+// recursing into nested collections as nested item groups. parentHasAuth
+// reports whether an enclosing folder or the collection defines auth.
+func convertCollection(collection HTTPieCollection, parentHasAuth bool) PostmanItem {
 	folder := PostmanItem{
-		Name: httpie.Entry.Name,
+		Name: collection.Name,
 		Item: []PostmanItem{},
+		Auth: convertScopedAuth(collection.Auth, parentHasAuth),
 	}
 
-	// Convert direct requests (if any)
-	for _, req := range httpie.Entry.Requests {
-		postmanItem := convertRequest(req)
-		folder.Item = append(folder.Item, postmanItem)
+	hasAuth := parentHasAuth
+	if folder.Auth != nil {
+		hasAuth = folder.Auth.Type != "noauth"
 	}
 
-	// Convert collections (folders)
-	for _, collection := range httpie.Entry.Collections {
-		// Add requests to this folder - for now we'll flatten them
-		// In a more complex version, we could create nested folders
-		for _, req := range collection.Requests {
-			postmanItem := convertRequest(req)
-			folder.Item = append(folder.Item, postmanItem)
-		}
+	for _, req := range collection.Requests {
+		folder.Item = append(folder.Item, convertRequest(req, hasAuth))
 	}
 
-	if len(folder.Item) > 0 {
-		postman.Item = append(postman.Item, folder)
+	for _, sub := range collection.Collections {
+		folder.Item = append(folder.Item, convertCollection(sub, hasAuth))
 	}
 
-	return postman
+	return folder
+}
+
+// forEachRequest calls fn for every request in the workspace, including
+// requests inside nested collections.
+func forEachRequest(entry HTTPieEntry, fn func(HTTPieRequest)) {
+	for _, req := range entry.Requests {
+		fn(req)
+	}
+	for _, collection := range entry.Collections {
+		forEachCollectionRequest(collection, fn)
+	}
 }
 
+func forEachCollectionRequest(collection HTTPieCollection, fn func(HTTPieRequest)) {
+	for _, req := range collection.Requests {
+		fn(req)
+	}
+	for _, sub := range collection.Collections {
+		forEachCollectionRequest(sub, fn)
+	}
+}
+
+// forEachCollection calls fn for every collection in the workspace,
+// parents before their nested collections.
+func forEachCollection(entry HTTPieEntry, fn func(HTTPieCollection)) {
+	var walk func(HTTPieCollection)
+	walk = func(collection HTTPieCollection) {
+		fn(collection)
+		for _, sub := range collection.Collections {
+			walk(sub)
+		}
+	}
+	for _, collection := range entry.Collections {
+		walk(collection)
+	}
+}
 
 func countTotalRequests(httpie HTTPieWorkspace) int {
-	count := len(httpie.Entry.Requests)
-	for _, collection := range httpie.Entry.Collections {
-		count += len(collection.Requests)
+	count := 0
+	forEachRequest(httpie.Entry, func(HTTPieRequest) {
+		count++
+	})
+	return count
+}
+
+// countPostmanRequests counts request items across all nested folders.
+func countPostmanRequests(items []PostmanItem) int {
+	count := 0
+	for _, item := range items {
+		if item.Request != nil {
+			count++
+		}
+		count += countPostmanRequests(item.Item)
 	}
 	return count
 }
@@ -543,7 +1163,7 @@ func countTotalRequests(httpie HTTPieWorkspace) int {
 func convertToPostman(httpie HTTPieWorkspace) PostmanCollection {
 	postman := PostmanCollection{
 		Info: PostmanInfo{
-			PostmanID:   generatePostmanID(),
+			PostmanID:   generatePostmanID("collection", httpie.Entry.Name),
 			Name:        httpie.Entry.Name,
 			Description: "Converted from HTTPie collection",
 			Schema:      "https://schema.getpostman.com/json/collection/v2.1.0/collection.json",
@@ -553,39 +1173,53 @@ func convertToPostman(httpie HTTPieWorkspace) PostmanCollection {
 	}
 
 	for _, req := range httpie.Entry.Requests {
-		postmanItem := convertRequest(req)
+		postmanItem := convertRequest(req, false)
 		postman.Item = append(postman.Item, postmanItem)
 	}
 
 	return postman
 }
 
-func convertRequest(httpieReq HTTPieRequest) PostmanItem {
+// convertRequest converts a single HTTPie request. parentHasAuth reports
+// whether an enclosing folder or the collection defines auth, in which case
+// an explicit "none" must become Postman's noauth instead of inheriting it.
+func convertRequest(httpieReq HTTPieRequest, parentHasAuth bool) PostmanItem {
+	// Generate a name if empty
+	name := httpieReq.Name
+	if name == "" {
+		name = httpieReq.Method + " " + httpieReq.URL
+	}
+
 	postmanReq := PostmanRequest{
 		Method: httpieReq.Method,
 		Header: convertHeaders(httpieReq.Headers),
 		URL:    convertURL(httpieReq.URL),
-		Auth:   convertAuth(httpieReq.Auth),
+		Auth:   convertScopedAuth(httpieReq.Auth, parentHasAuth),
 	}
+	applyPathParams(&postmanReq.URL, httpieReq.PathParams)
+	applyQueryParams(&postmanReq.URL, httpieReq.QueryParams)
 
 	// Convert body if present
-	if httpieReq.Body.Type != "none" && httpieReq.Body.Text.Value != "" {
-		postmanReq.Body = &PostmanBody{
-			Mode: "raw",
-			Raw:  httpieReq.Body.Text.Value,
-		}
-
-		// Set options for JSON content
-		if httpieReq.Body.Text.Format == "application/json" {
-			postmanReq.Body.Options = &PostmanBodyOptions{
-				Raw: PostmanBodyRaw{
-					Language: "json",
-				},
+	body, contentType := convertBody(name, httpieReq.Body)
+	if body != nil {
+		postmanReq.Body = body
+
+		// A multipart Content-Type, with or without a boundary, would replace
+		// the one Postman generates for the boundary it picks, and the server
+		// could not parse the body
+		if body.Mode == "formdata" {
+			var headers []PostmanHeader
+			for _, header := range postmanReq.Header {
+				isMultipart := strings.HasPrefix(strings.ToLower(strings.TrimSpace(header.Value)), "multipart/")
+				if !strings.EqualFold(header.Key, "Content-Type") || !isMultipart {
+					headers = append(headers, header)
+				}
 			}
+			postmanReq.Header = headers
 		}
 
 		// Add Content-Type header if not present and body has format
-		if httpieReq.Body.Text.Format != "" {
+		if contentType != "" {
 			hasContentType := false
 			for _, header := range postmanReq.Header {
 				if strings.ToLower(header.Key) == "content-type" {
@@ -596,26 +1230,149 @@ func convertRequest(httpieReq HTTPieRequest) PostmanItem {
 			if !hasContentType {
 				postmanReq.Header = append(postmanReq.Header, PostmanHeader{
 					Key:   "Content-Type",
-					Value: httpieReq.Body.Text.Format,
+					Value: contentType,
 				})
 			}
 		}
 	}
 
-	// Generate a name if empty
-	name := httpieReq.Name
-	if name == "" {
-		name = httpieReq.Method + " " + httpieReq.URL
-	}
-
 	return PostmanItem{
 		Name:    name,
 		Request: &postmanReq,
 	}
 }
 
+// convertBody converts an HTTPie body into a Postman body, returning the
+// content type implied by the body (empty if none). The request name is
+// only used for warnings.
+func convertBody(name string, httpieBody HTTPieBody) (*PostmanBody, string) {
+	switch httpieBody.Type {
+	case "none":
+		return nil, ""
+	case "form":
+		return convertFormBody(httpieBody.Form)
+	case "graphql":
+		return convertGraphQLBody(name, httpieBody.GraphQL)
+	case "file":
+		if httpieBody.File.Name == "" {
+			return nil, ""
+		}
+		return &PostmanBody{
+			Mode: "file",
+			File: &PostmanBodyFile{Src: rewriteFilePath(httpieBody.File.Name)},
+		}, ""
+	}
+
+	if httpieBody.Text.Value == "" {
+		return nil, ""
+	}
+
+	body := &PostmanBody{
+		Mode: "raw",
+		Raw:  httpieBody.Text.Value,
+	}
+
+	// Set options for JSON content
+	if httpieBody.Text.Format == "application/json" {
+		body.Options = &PostmanBodyOptions{
+			Raw: PostmanBodyRaw{
+				Language: "json",
+			},
+		}
+	}
+
+	return body, httpieBody.Text.Format
+}
+
+// convertFormBody maps HTTPie form fields to Postman's urlencoded or
+// formdata body mode depending on whether the form is multipart.
+func convertFormBody(form HTTPieForm) (*PostmanBody, string) {
+	if len(form.Fields) == 0 {
+		return nil, ""
+	}
+
+	if form.IsMultipart {
+		body := &PostmanBody{Mode: "formdata"}
+		for _, field := range form.Fields {
+			param := PostmanFormDataParam{
+				Key:      field.Name,
+				Type:     "text",
+				Disabled: !field.Enabled,
+			}
+			if field.Type == "file" {
+				param.Type = "file"
+				param.Src = rewriteFilePath(field.Value)
+			} else {
+				param.Value = field.Value
+			}
+			body.FormData = append(body.FormData, param)
+		}
+		// No Content-Type: Postman adds one with the boundary it generates
+		return body, ""
+	}
+
+	body := &PostmanBody{Mode: "urlencoded"}
+	for _, field := range form.Fields {
+		body.URLEncoded = append(body.URLEncoded, PostmanURLEncodedParam{
+			Key:      field.Name,
+			Value:    field.Value,
+			Type:     "text",
+			Disabled: !field.Enabled,
+		})
+	}
+	return body, "application/x-www-form-urlencoded"
+}
+
+// rewriteFilePath makes an HTTPie file path relative to options.FileBaseDir,
+// so converted collections work on machines other than the exporter's.
+func rewriteFilePath(path string) string {
+	if options.FileBaseDir == "" || path == "" {
+		return path
+	}
+
+	rel, err := filepath.Rel(options.FileBaseDir, path)
+	if err != nil {
+		log.Printf("Warning: cannot make %s relative to %s: %v", path, options.FileBaseDir, err)
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// convertGraphQLBody maps an HTTPie GraphQL body to Postman's graphql mode.
+// Variables that are not valid JSON are kept as-is, with a warning.
+func convertGraphQLBody(name string, gql HTTPieGraphQL) (*PostmanBody, string) {
+	if gql.Query == "" && gql.Variables == "" {
+		return nil, ""
+	}
+
+	variables := strings.TrimSpace(gql.Variables)
+	if variables != "" && !json.Valid([]byte(variables)) {
+		log.Printf("Warning: request %q has invalid GraphQL variables JSON; copying it verbatim.", name)
+	}
+
+	return &PostmanBody{
+		Mode: "graphql",
+		GraphQL: &PostmanGraphQL{
+			Query:     gql.Query,
+			Variables: variables,
+		},
+	}, "application/json"
+}
+
+// convertScopedAuth converts auth for a folder or request nested under a
+// parent. A missing auth (nil) tells Postman to inherit from the parent, so
+// "none" only maps to nil when there is nothing to inherit.
+func convertScopedAuth(httpieAuth HTTPieAuth, parentHasAuth bool) *PostmanAuth {
+	if httpieAuth.Type == "none" && parentHasAuth {
+		return &PostmanAuth{Type: "noauth"}
+	}
+	return convertAuth(httpieAuth)
+}
+
 func convertAuth(httpieAuth HTTPieAuth) *PostmanAuth {
-	if httpieAuth.Type == "none" || httpieAuth.Type == "" {
+	switch httpieAuth.Type {
+	case "", "none", "inherit", "inherited":
+		// Inherited auth is expressed in Postman by omitting the auth block
 		return nil
 	}
 
@@ -689,59 +1446,332 @@ func convertHeaders(httpieHeaders []HTTPieHeader) []PostmanHeader {
 	return postmanHeaders
 }
 
+// convertURL decomposes a URL into Postman's structured form. It parses the
+// URL by hand rather than with net/url so that templated hosts such as
+// {{base_url}}, IPv6 literals and percent-encoded paths survive unchanged.
 func convertURL(httpieURL string) PostmanURL {
-	// Parse the URL
-	parsedURL, err := url.Parse(httpieURL)
-	if err != nil {
-		// If parsing fails, return raw URL
-		return PostmanURL{
-			Raw: httpieURL,
+	postmanURL := PostmanURL{Raw: httpieURL}
+	rest := httpieURL
+
+	// Extract hash
+	if before, hash, found := strings.Cut(rest, "#"); found {
+		rest = before
+		postmanURL.Hash = hash
+	}
+
+	// Extract query parameters, keeping their original order and encoding
+	if before, rawQuery, found := strings.Cut(rest, "?"); found {
+		rest = before
+		for _, pair := range strings.Split(rawQuery, "&") {
+			if pair == "" {
+				continue
+			}
+			key, value, _ := strings.Cut(pair, "=")
+			postmanURL.Query = append(postmanURL.Query, PostmanQueryParam{
+				Key:   key,
+				Value: value,
+			})
 		}
 	}
 
-	// Extract host
-	var host []string
-	if parsedURL.Host != "" {
-		host = []string{parsedURL.Scheme + "://" + parsedURL.Host}
-	} else {
-		// Handle cases where URL starts with variable like {{YOU}}
-		urlParts := strings.Split(httpieURL, "/")
-		if len(urlParts) > 0 {
-			host = []string{urlParts[0]}
+	// Extract protocol
+	if match := urlProtocolRegex.FindStringSubmatch(rest); match != nil {
+		postmanURL.Protocol = match[1]
+		rest = rest[len(match[0]):]
+	}
+
+	// Split authority from path; a leading "/" means there is no host
+	authority, path := rest, ""
+	if i := strings.Index(rest, "/"); i >= 0 {
+		authority, path = rest[:i], rest[i:]
+	}
+
+	// Drop userinfo, which Postman expresses through auth instead
+	if i := strings.LastIndex(authority, "@"); i >= 0 {
+		authority = authority[i+1:]
+	}
+
+	// Extract host and port
+	if authority != "" {
+		host, port := splitHostPort(authority)
+		postmanURL.Port = port
+		if strings.HasPrefix(host, "[") {
+			// IPv6 literals are a single host element
+			postmanURL.Host = []string{host}
+		} else {
+			postmanURL.Host = splitOutsideVariables(host, '.')
+		}
+	}
+
+	// Extract path, keeping empty segments (e.g. a trailing slash)
+	if path != "" && path != "/" {
+		postmanURL.Path = splitOutsideVariables(strings.TrimPrefix(path, "/"), '/')
+	}
+
+	return postmanURL
+}
+
+// postmanURLString rebuilds a raw URL from its structured parts, for
+// collections that only carry the decomposed form.
+func postmanURLString(postmanURL PostmanURL) string {
+	var b strings.Builder
+	if postmanURL.Protocol != "" {
+		b.WriteString(postmanURL.Protocol + "://")
+	}
+	b.WriteString(strings.Join(postmanURL.Host, "."))
+	if postmanURL.Port != "" {
+		b.WriteString(":" + postmanURL.Port)
+	}
+	if len(postmanURL.Path) > 0 {
+		b.WriteString("/" + strings.Join(postmanURL.Path, "/"))
+	}
+
+	var pairs []string
+	for _, param := range postmanURL.Query {
+		if !param.Disabled {
+			pairs = append(pairs, param.Key+"="+param.Value)
+		}
+	}
+	if len(pairs) > 0 {
+		b.WriteString("?" + strings.Join(pairs, "&"))
+	}
+	if postmanURL.Hash != "" {
+		b.WriteString("#" + postmanURL.Hash)
+	}
+	return b.String()
+}
+
+var (
+	urlProtocolRegex = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9+.-]*)://`)
+	urlPortRegex     = regexp.MustCompile(`^(\d+|\{\{[^}]+\}\})$`)
+)
+
+// splitHostPort splits "host:port", where the port may be a number or a
+// {{variable}}. IPv6 literals must be bracketed, as in "[::1]:8080".
+func splitHostPort(authority string) (string, string) {
+	if strings.HasPrefix(authority, "[") {
+		end := strings.Index(authority, "]")
+		if end < 0 {
+			return authority, ""
+		}
+		host, rest := authority[:end+1], authority[end+1:]
+		if port, found := strings.CutPrefix(rest, ":"); found {
+			return host, port
+		}
+		return host, ""
+	}
+
+	i := strings.LastIndex(authority, ":")
+	if i < 0 || !urlPortRegex.MatchString(authority[i+1:]) {
+		return authority, ""
+	}
+	return authority[:i], authority[i+1:]
+}
+
+// splitOutsideVariables splits s on sep, ignoring separators that appear
+// inside {{variable}} references.
+func splitOutsideVariables(s string, sep byte) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], "{{"):
+			depth++
+			i++
+		case strings.HasPrefix(s[i:], "}}") && depth > 0:
+			depth--
+			i++
+		case s[i] == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
 		}
 	}
+	return append(parts, s[start:])
+}
 
-	// Extract path
-	var path []string
-	if parsedURL.Path != "" && parsedURL.Path != "/" {
-		pathParts := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
-		for _, part := range pathParts {
-			if part != "" {
-				path = append(path, part)
+// applyPathParams turns enabled HTTPie path params into Postman path
+// variables, rewriting {name} segments to Postman's :name syntax. Postman
+// only substitutes path variables in the path, so a param with no {name}
+// segment (e.g. one used in the query) gets no variable.
+func applyPathParams(postmanURL *PostmanURL, params []HTTPiePathParam) {
+	for _, param := range params {
+		if !param.Enabled || param.Name == "" {
+			continue
+		}
+
+		placeholder := ":" + param.Name
+		rewritten := false
+		for i, segment := range postmanURL.Path {
+			if segment == "{"+param.Name+"}" {
+				postmanURL.Path[i] = placeholder
+				rewritten = true
 			}
 		}
+
+		// Match /{name} only as a whole segment so {{name}} variables are untouched
+		segmentRegex := regexp.MustCompile(`/\{` + regexp.QuoteMeta(param.Name) + `\}([/?#]|$)`)
+		if raw := segmentRegex.ReplaceAllString(postmanURL.Raw, "/"+placeholder+"$1"); raw != postmanURL.Raw {
+			postmanURL.Raw = raw
+			rewritten = true
+		}
+
+		if !rewritten {
+			log.Printf("Warning: path param %q is not a path segment of %s; Postman cannot substitute it.", param.Name, postmanURL.Raw)
+			continue
+		}
+
+		postmanURL.Variable = append(postmanURL.Variable, PostmanURLVariable{
+			Key:         param.Name,
+			Value:       param.Value,
+			Description: param.Description,
+		})
 	}
+}
 
-	// Extract query parameters
-	var query []PostmanQueryParam
-	if parsedURL.RawQuery != "" {
-		queryParams, _ := url.ParseQuery(parsedURL.RawQuery)
-		for key, values := range queryParams {
-			for _, value := range values {
-				query = append(query, PostmanQueryParam{
-					Key:   key,
-					Value: value,
-				})
+// unescapeQueryComponent decodes a query key or value, falling back to the
+// raw text when it is not valid percent-encoding.
+func unescapeQueryComponent(s string) string {
+	if unescaped, err := url.QueryUnescape(s); err == nil {
+		return unescaped
+	}
+	return s
+}
+
+// applyQueryParams merges HTTPie's structured query params into the params
+// parsed from the URL. The n-th param named k in the table matches the n-th
+// param named k in the URL and takes its value and enabled state from the
+// table (disabled ones are dropped from the raw URL). Params only in the
+// table are appended, and enabled ones are also added to the raw URL. Keys
+// and values are escaped with escapeQueryComponent.
+func applyQueryParams(postmanURL *PostmanURL, params []HTTPieQueryParam) {
+	occurrences := map[string]int{}
+	for _, param := range params {
+		if param.Name == "" {
+			continue
+		}
+		occurrence := occurrences[param.Name]
+		occurrences[param.Name]++
+
+		key, value := escapeQueryComponent(param.Name, true), escapeQueryComponent(param.Value, false)
+
+		index := -1
+		for i, existing := range postmanURL.Query {
+			if unescapeQueryComponent(existing.Key) != param.Name {
+				continue
+			}
+			if occurrence == 0 {
+				index = i
+				break
+			}
+			occurrence--
+		}
+
+		if index < 0 {
+			postmanURL.Query = append(postmanURL.Query, PostmanQueryParam{
+				Key:      key,
+				Value:    value,
+				Disabled: !param.Enabled,
+			})
+			if param.Enabled {
+				postmanURL.Raw = appendRawQuery(postmanURL.Raw, key+"="+value)
 			}
+			continue
+		}
+
+		existing := &postmanURL.Query[index]
+		pair := existing.Key + "=" + existing.Value
+		edited := unescapeQueryComponent(existing.Value) != param.Value
+		if edited {
+			existing.Value = value
+		}
+		switch {
+		case !param.Enabled && !existing.Disabled:
+			postmanURL.Raw = removeRawQuery(postmanURL.Raw, pair)
+			existing.Disabled = true
+		case param.Enabled && !existing.Disabled && edited:
+			postmanURL.Raw = replaceRawQuery(postmanURL.Raw, pair, existing.Key+"="+value)
 		}
 	}
+}
 
-	return PostmanURL{
-		Raw:   httpieURL,
-		Host:  host,
-		Path:  path,
-		Query: query,
+// escapeQueryComponent percent-encodes only the characters that would
+// change how a query key or value is split or decoded: spaces, &, #, + and
+// %, plus = in keys. Sub-delimiters such as , : and / stay literal, so the
+// URL sends the same bytes as HTTPie, and {{variable}} references are kept.
+func escapeQueryComponent(s string, isKey bool) string {
+	escape := func(text string) string {
+		var b strings.Builder
+		for i := 0; i < len(text); i++ {
+			c := text[i]
+			if c == ' ' || c == '&' || c == '#' || c == '+' || c == '%' || (isKey && c == '=') {
+				fmt.Fprintf(&b, "%%%02X", c)
+			} else {
+				b.WriteByte(c)
+			}
+		}
+		return b.String()
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, match := range templateVariableRegex.FindAllStringIndex(s, -1) {
+		b.WriteString(escape(s[last:match[0]]))
+		b.WriteString(s[match[0]:match[1]])
+		last = match[1]
+	}
+	b.WriteString(escape(s[last:]))
+	return b.String()
+}
+
+// appendRawQuery adds a key=value pair to a raw URL, before any fragment.
+func appendRawQuery(raw, pair string) string {
+	base, fragment, hasFragment := strings.Cut(raw, "#")
+	separator := "?"
+	if strings.Contains(base, "?") {
+		separator = "&"
+	}
+	base += separator + pair
+	if hasFragment {
+		base += "#" + fragment
 	}
+	return base
+}
+
+// removeRawQuery removes the first occurrence of a key=value pair from the
+// query string of a raw URL.
+func removeRawQuery(raw, pair string) string {
+	return replaceRawQuery(raw, pair, "")
+}
+
+// replaceRawQuery replaces the first occurrence of a key=value pair in the
+// query string of a raw URL; an empty replacement removes it.
+func replaceRawQuery(raw, pair, replacement string) string {
+	base, fragment, hasFragment := strings.Cut(raw, "#")
+	path, rawQuery, hasQuery := strings.Cut(base, "?")
+	if !hasQuery {
+		return raw
+	}
+
+	pairs := strings.Split(rawQuery, "&")
+	for i, p := range pairs {
+		if p == pair || (p == strings.TrimSuffix(pair, "=") && strings.HasSuffix(pair, "=")) {
+			if replacement == "" {
+				pairs = append(pairs[:i], pairs[i+1:]...)
+			} else {
+				pairs[i] = replacement
+			}
+			break
+		}
+	}
+
+	result := path
+	if len(pairs) > 0 {
+		result += "?" + strings.Join(pairs, "&")
+	}
+	if hasFragment {
+		result += "#" + fragment
+	}
+	return result
 }
 
 func extractVariablesFromWorkspace(httpie HTTPieWorkspace) []PostmanVariable {
@@ -777,83 +1807,98 @@ func extractVariablesFromWorkspace(httpie HTTPieWorkspace) []PostmanVariable {
 		}
 	}
 
-	// Add environment variables to variableSet
-	for k, v := range envVarMap {
-		variableSet[k] = v
+	// Add environment variables to variableSet, unless environments are
+	// exported as separate files; then only referenced names are kept
+	if options.EnvironmentDir == "" {
+		for k, v := range envVarMap {
+			variableSet[k] = v
+		}
 	}
 
 	// Extract variables from all URLs and headers (as before)
 	variableRegex := regexp.MustCompile(`\{\{([^}]+)\}\}`)
 
-	// Process direct requests
-	for _, req := range httpie.Entry.Requests {
+	// Process all requests, including those in nested collections
+	forEachRequest(httpie.Entry, func(req HTTPieRequest) {
 		extractVariablesFromRequest(req, variableRegex, variableSet, envVarMap)
+	})
+
+	// Process workspace- and collection-level auth
+	authScopes := []HTTPieAuth{httpie.Entry.Auth}
+	forEachCollection(httpie.Entry, func(collection HTTPieCollection) {
+		authScopes = append(authScopes, collection.Auth)
+	})
+	for _, auth := range authScopes {
+		extractVariablesFromRequest(HTTPieRequest{Auth: auth}, variableRegex, variableSet, envVarMap)
 	}
 
-	// Process collections
-	for _, collection := range httpie.Entry.Collections {
-		for _, req := range collection.Requests {
-			extractVariablesFromRequest(req, variableRegex, variableSet, envVarMap)
-		}
-	}
-
-	// Convert map to slice
+	// Convert map to slice, keeping variables that are secret in any
+	// environment secret in the collection
+	secrets := secretVariableNames(httpie.Environments)
 	for varName, varValue := range variableSet {
+		varType := "string"
+		if secrets[varName] {
+			varType = "secret"
+		}
 		variables = append(variables, PostmanVariable{
-			ID:    uuid.New().String(), // Generate a unique ID for each variable
+			ID:    newID("variable", varName), // Generate a unique ID for each variable
 			Key:   varName,
 			Value: varValue,
-			Type:  "string",
+			Type:  varType,
 		})
 	}
+	sortVariables(variables)
 
 	return variables
 }
 
 // Updated signature to accept envVarMap
 func extractVariablesFromRequest(req HTTPieRequest, variableRegex *regexp.Regexp, variableSet map[string]string, envVarMap map[string]string) {
-	// Extract from URL
-	matches := variableRegex.FindAllStringSubmatch(req.URL, -1)
-	for _, match := range matches {
-		if len(match) > 1 {
-			varName := match[1]
-			if val, exists := envVarMap[varName]; exists {
-				variableSet[varName] = val
-			} else if _, exists := variableSet[varName]; !exists {
-				variableSet[varName] = "" // Empty default value
-			}
-		}
-	}
-
-	// Extract from headers
-	for _, header := range req.Headers {
-		matches := variableRegex.FindAllStringSubmatch(header.Value, -1)
+	collect := func(text string) {
+		matches := variableRegex.FindAllStringSubmatch(text, -1)
 		for _, match := range matches {
 			if len(match) > 1 {
 				varName := match[1]
 				if val, exists := envVarMap[varName]; exists {
 					variableSet[varName] = val
 				} else if _, exists := variableSet[varName]; !exists {
-					variableSet[varName] = ""
+					variableSet[varName] = "" // Empty default value
 				}
 			}
 		}
 	}
 
-	// Extract from body
-	if req.Body.Text.Value != "" {
-		matches := variableRegex.FindAllStringSubmatch(req.Body.Text.Value, -1)
-		for _, match := range matches {
-			if len(match) > 1 {
-				varName := match[1]
-				if val, exists := envVarMap[varName]; exists {
-					variableSet[varName] = val
-				} else if _, exists := variableSet[varName]; !exists {
-					variableSet[varName] = ""
-				}
-			}
-		}
+	// Extract from URL
+	collect(req.URL)
+
+	// Extract from auth credentials
+	collect(req.Auth.Credentials.Username)
+	collect(req.Auth.Credentials.Password)
+
+	// Extract from headers
+	for _, header := range req.Headers {
+		collect(header.Value)
+	}
+
+	// Extract from path and query params
+	for _, param := range req.PathParams {
+		collect(param.Value)
+	}
+	for _, param := range req.QueryParams {
+		collect(param.Value)
+	}
+
+	// Extract from form fields
+	for _, field := range req.Body.Form.Fields {
+		collect(field.Value)
 	}
+
+	// Extract from body
+	collect(req.Body.Text.Value)
+
+	// Extract from GraphQL query and variables
+	collect(req.Body.GraphQL.Query)
+	collect(req.Body.GraphQL.Variables)
 }
 
 func extractVariables(httpie HTTPieWorkspace) []PostmanVariable {
@@ -861,10 +1906,45 @@ func extractVariables(httpie HTTPieWorkspace) []PostmanVariable {
 	return extractVariablesFromWorkspace(httpie)
 }
 
-func generatePostmanID() string {
+// idNamespace is the UUIDv5 namespace for IDs generated in deterministic mode.
+var idNamespace = uuid.NewSHA1(uuid.NameSpaceURL, []byte("https://github.com/vuon9/postmanzier"))
+
+// generatePostmanID returns a collection ID. In deterministic mode it is
+// derived from seed (e.g. source file and collection name); otherwise it
+// is time-based.
+func generatePostmanID(seed ...string) string {
+	if options.Deterministic {
+		return newID(seed...)
+	}
 	return fmt.Sprintf("%d", time.Now().UnixNano())
 }
 
+// newID returns a random UUID, or in deterministic mode a UUIDv5 derived
+// from parts.
+func newID(parts ...string) string {
+	if options.Deterministic {
+		return uuid.NewSHA1(idNamespace, []byte(strings.Join(parts, "\x00"))).String()
+	}
+	return uuid.New().String()
+}
+
+// sourceNames returns the base names of input files, for seeding IDs.
+func sourceNames(paths []string) []string {
+	names := make([]string, len(paths))
+	for i, path := range paths {
+		names[i] = filepath.Base(path)
+	}
+	return names
+}
+
+// sortVariables orders variables by key so output does not depend on map
+// iteration order.
+func sortVariables(variables []PostmanVariable) {
+	sort.Slice(variables, func(i, j int) bool {
+		return variables[i].Key < variables[j].Key
+	})
+}
+
 func generateUniqueFilename(basePath string) string {
 	if _, err := os.Stat(basePath); os.IsNotExist(err) {
 		return basePath
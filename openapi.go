@@ -0,0 +1,557 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// OpenAPI 3.1 document structures (only the parts the converters use)
+type OpenAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Servers    []OpenAPIServer                         `json:"servers,omitempty"`
+	Tags       []OpenAPITag                            `json:"tags,omitempty"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Components *OpenAPIComponents                      `json:"components,omitempty"`
+}
+
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type OpenAPIServer struct {
+	URL       string                           `json:"url"`
+	Variables map[string]OpenAPIServerVariable `json:"variables,omitempty"`
+}
+
+type OpenAPIServerVariable struct {
+	Default string `json:"default"`
+}
+
+type OpenAPITag struct {
+	Name string `json:"name"`
+}
+
+type OpenAPIOperation struct {
+	Tags        []string                   `json:"tags,omitempty"`
+	Summary     string                     `json:"summary,omitempty"`
+	OperationID string                     `json:"operationId,omitempty"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses"`
+	Security    []map[string][]string      `json:"security,omitempty"`
+}
+
+type OpenAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required,omitempty"`
+	Schema   *OpenAPISchema `json:"schema,omitempty"`
+	Example  any            `json:"example,omitempty"`
+}
+
+type OpenAPIRequestBody struct {
+	Content map[string]OpenAPIMediaType `json:"content"`
+}
+
+type OpenAPIMediaType struct {
+	Schema   *OpenAPISchema            `json:"schema,omitempty"`
+	Examples map[string]OpenAPIExample `json:"examples,omitempty"`
+}
+
+type OpenAPIExample struct {
+	Summary string `json:"summary,omitempty"`
+	Value   any    `json:"value"`
+}
+
+type OpenAPIResponse struct {
+	Description string `json:"description"`
+}
+
+type OpenAPISchema struct {
+	Type       string                    `json:"type,omitempty"`
+	Format     string                    `json:"format,omitempty"`
+	Properties map[string]*OpenAPISchema `json:"properties,omitempty"`
+	Example    any                       `json:"example,omitempty"`
+}
+
+type OpenAPIComponents struct {
+	SecuritySchemes map[string]OpenAPISecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type OpenAPISecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+	In     string `json:"in,omitempty"`
+	Name   string `json:"name,omitempty"`
+}
+
+func handleToOpenAPICommand() {
+	fs := flag.NewFlagSet("to-openapi", flag.ExitOnError)
+	args := parseArgs(fs, os.Args[2:])
+
+	if len(args) < 2 {
+		fmt.Println("Usage: postmanzier to-openapi <input-collection> <output-openapi-file>")
+		fmt.Println("Example: postmanzier to-openapi collection.json openapi.json")
+		os.Exit(1)
+	}
+
+	collection, err := loadCollection(args[0])
+	if err != nil {
+		log.Fatalf("Error reading input collection: %v", err)
+	}
+
+	document := convertPostmanToOpenAPI(collection)
+
+	outputData, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		log.Fatalf("Error marshaling OpenAPI document: %v", err)
+	}
+
+	finalOutputPath := generateUniqueFilename(args[1])
+	if err := os.WriteFile(finalOutputPath, outputData, 0644); err != nil {
+		log.Fatalf("Error writing output file: %v", err)
+	}
+
+	operations := 0
+	for _, pathItem := range document.Paths {
+		operations += len(pathItem)
+	}
+
+	fmt.Println("OpenAPI export completed!")
+	fmt.Printf("* Total APIs: %d\n", countPostmanRequests(collection.Item))
+	fmt.Printf("* Total operations: %d\n", operations)
+	fmt.Printf("--> Output file: %s\n", finalOutputPath)
+}
+
+// openAPIBuilder accumulates operations while walking a collection.
+type openAPIBuilder struct {
+	document     OpenAPIDocument
+	variables    map[string]string
+	operationIDs map[string]bool
+	tags         map[string]bool
+	servers      map[string]bool
+}
+
+// convertPostmanToOpenAPI builds an OpenAPI 3.1 document from a collection.
+// Requests sharing a method and templated path become a single operation.
+func convertPostmanToOpenAPI(collection PostmanCollection) OpenAPIDocument {
+	b := &openAPIBuilder{
+		document: OpenAPIDocument{
+			OpenAPI: "3.1.0",
+			Info: OpenAPIInfo{
+				Title:       collection.Info.Name,
+				Description: collection.Info.Description,
+				Version:     "1.0.0",
+			},
+			Paths: map[string]map[string]*OpenAPIOperation{},
+		},
+		variables:    map[string]string{},
+		operationIDs: map[string]bool{},
+		tags:         map[string]bool{},
+		servers:      map[string]bool{},
+	}
+	if b.document.Info.Title == "" {
+		b.document.Info.Title = "Converted collection"
+	}
+
+	for _, v := range collection.Variable {
+		b.variables[v.Key] = v.Value
+	}
+
+	b.addItems(collection.Item, "", collection.Auth)
+	return b.document
+}
+
+func (b *openAPIBuilder) addItems(items []PostmanItem, folder string, auth *PostmanAuth) {
+	for _, item := range items {
+		if item.Request == nil {
+			folderAuth := auth
+			if item.Auth != nil {
+				folderAuth = item.Auth
+			}
+			b.addItems(item.Item, item.Name, folderAuth)
+			continue
+		}
+
+		requestAuth := auth
+		if item.Request.Auth != nil {
+			requestAuth = item.Request.Auth
+		}
+		b.addRequest(item, folder, requestAuth)
+	}
+}
+
+var (
+	openAPIVariableRegex = regexp.MustCompile(`\{\{\s*([^}]+?)\s*\}\}`)
+	jsonContentTypeRegex = regexp.MustCompile(`(?i)[/+]json\b`)
+)
+
+func (b *openAPIBuilder) addRequest(item PostmanItem, folder string, auth *PostmanAuth) {
+	req := item.Request
+	method := strings.ToLower(req.Method)
+	if method == "" {
+		method = "get"
+	}
+
+	b.addServer(req.URL)
+	path, pathParams := openAPIPath(req.URL)
+
+	if b.document.Paths[path] == nil {
+		b.document.Paths[path] = map[string]*OpenAPIOperation{}
+	}
+	operation := b.document.Paths[path][method]
+	if operation == nil {
+		operation = &OpenAPIOperation{
+			Summary:     item.Name,
+			OperationID: b.uniqueOperationID(item.Name, method, path),
+			Responses: map[string]OpenAPIResponse{
+				"default": {Description: "Default response"},
+			},
+		}
+		if folder != "" {
+			operation.Tags = []string{folder}
+			if !b.tags[folder] {
+				b.tags[folder] = true
+				b.document.Tags = append(b.document.Tags, OpenAPITag{Name: folder})
+			}
+		}
+		b.document.Paths[path][method] = operation
+	}
+
+	// Path parameters
+	pathValues := map[string]string{}
+	for _, v := range req.URL.Variable {
+		pathValues[v.Key] = v.Value
+	}
+	for _, name := range pathParams {
+		value, ok := pathValues[name]
+		if !ok {
+			value = b.variables[name]
+		}
+		addOpenAPIParameter(operation, OpenAPIParameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &OpenAPISchema{Type: "string"},
+			Example:  exampleValue(value),
+		})
+	}
+
+	// Query parameters
+	for _, param := range req.URL.Query {
+		if param.Key == "" || param.Disabled {
+			continue
+		}
+		addOpenAPIParameter(operation, OpenAPIParameter{
+			Name:    unescapeQueryComponent(param.Key),
+			In:      "query",
+			Schema:  &OpenAPISchema{Type: "string"},
+			Example: exampleValue(unescapeQueryComponent(param.Value)),
+		})
+	}
+
+	// Header parameters; OpenAPI ignores Accept, Content-Type and Authorization
+	for _, header := range req.Header {
+		if header.Disabled {
+			continue
+		}
+		switch strings.ToLower(header.Key) {
+		case "accept", "content-type", "authorization":
+			continue
+		}
+		addOpenAPIParameter(operation, OpenAPIParameter{
+			Name:    header.Key,
+			In:      "header",
+			Schema:  &OpenAPISchema{Type: "string"},
+			Example: exampleValue(header.Value),
+		})
+	}
+
+	b.addRequestBody(operation, item)
+
+	if scheme := b.addSecurityScheme(auth); scheme != "" {
+		found := false
+		for _, requirement := range operation.Security {
+			if _, ok := requirement[scheme]; ok {
+				found = true
+			}
+		}
+		if !found {
+			operation.Security = append(operation.Security, map[string][]string{scheme: {}})
+		}
+	}
+}
+
+// openAPIPath converts a Postman URL path to an OpenAPI path template and
+// returns the path parameter names in order. Both :name segments and
+// {{name}} variables become {name} parameters.
+func openAPIPath(postmanURL PostmanURL) (string, []string) {
+	var params []string
+	seen := map[string]bool{}
+	addParam := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			params = append(params, name)
+		}
+	}
+
+	segments := make([]string, 0, len(postmanURL.Path))
+	for _, segment := range postmanURL.Path {
+		switch {
+		case strings.HasPrefix(segment, ":") && len(segment) > 1:
+			addParam(segment[1:])
+			segment = "{" + segment[1:] + "}"
+		case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") && !strings.HasPrefix(segment, "{{"):
+			addParam(strings.Trim(segment, "{}"))
+		default:
+			segment = openAPIVariableRegex.ReplaceAllStringFunc(segment, func(match string) string {
+				name := openAPIVariableRegex.FindStringSubmatch(match)[1]
+				addParam(name)
+				return "{" + name + "}"
+			})
+		}
+		segments = append(segments, segment)
+	}
+
+	return "/" + strings.Join(segments, "/"), params
+}
+
+// addServer records the request's scheme, host and port as a server,
+// turning {{variables}} into server variables.
+func (b *openAPIBuilder) addServer(postmanURL PostmanURL) {
+	if len(postmanURL.Host) == 0 {
+		return
+	}
+
+	serverURL := strings.Join(postmanURL.Host, ".")
+	if postmanURL.Port != "" {
+		serverURL += ":" + postmanURL.Port
+	}
+	if postmanURL.Protocol != "" {
+		serverURL = postmanURL.Protocol + "://" + serverURL
+	}
+
+	variables := map[string]OpenAPIServerVariable{}
+	serverURL = openAPIVariableRegex.ReplaceAllStringFunc(serverURL, func(match string) string {
+		name := openAPIVariableRegex.FindStringSubmatch(match)[1]
+		variables[name] = OpenAPIServerVariable{Default: b.variables[name]}
+		return "{" + name + "}"
+	})
+
+	if b.servers[serverURL] {
+		return
+	}
+	b.servers[serverURL] = true
+
+	server := OpenAPIServer{URL: serverURL}
+	if len(variables) > 0 {
+		server.Variables = variables
+	}
+	b.document.Servers = append(b.document.Servers, server)
+}
+
+func (b *openAPIBuilder) addRequestBody(operation *OpenAPIOperation, item PostmanItem) {
+	body := item.Request.Body
+	if body == nil {
+		return
+	}
+
+	contentType := postmanHeaderValue(item.Request.Header, "Content-Type")
+	var mediaType string
+	var media OpenAPIMediaType
+
+	switch body.Mode {
+	case "raw":
+		if body.Raw == "" {
+			return
+		}
+		if contentType == "" && body.Options != nil && body.Options.Raw.Language == "json" {
+			contentType = "application/json"
+		}
+		mediaType = contentType
+		if mediaType == "" {
+			mediaType = "text/plain"
+		}
+		var value any = body.Raw
+		if jsonContentTypeRegex.MatchString(mediaType) {
+			var parsed any
+			if err := json.Unmarshal([]byte(body.Raw), &parsed); err == nil {
+				value = parsed
+			}
+		}
+		media.Examples = map[string]OpenAPIExample{item.Name: {Summary: item.Name, Value: value}}
+	case "urlencoded", "formdata":
+		mediaType = "application/x-www-form-urlencoded"
+		if body.Mode == "formdata" {
+			mediaType = "multipart/form-data"
+		}
+		media.Schema = &OpenAPISchema{Type: "object", Properties: map[string]*OpenAPISchema{}}
+		example := map[string]any{}
+		for _, param := range body.URLEncoded {
+			if param.Disabled {
+				continue
+			}
+			media.Schema.Properties[param.Key] = &OpenAPISchema{Type: "string"}
+			example[param.Key] = param.Value
+		}
+		for _, param := range body.FormData {
+			if param.Disabled {
+				continue
+			}
+			if param.Type == "file" {
+				media.Schema.Properties[param.Key] = &OpenAPISchema{Type: "string", Format: "binary"}
+				continue
+			}
+			media.Schema.Properties[param.Key] = &OpenAPISchema{Type: "string"}
+			example[param.Key] = param.Value
+		}
+		media.Examples = map[string]OpenAPIExample{item.Name: {Summary: item.Name, Value: example}}
+	case "graphql":
+		if body.GraphQL == nil {
+			return
+		}
+		mediaType = "application/json"
+		value := map[string]any{"query": body.GraphQL.Query}
+		var variables any
+		if err := json.Unmarshal([]byte(body.GraphQL.Variables), &variables); err == nil {
+			value["variables"] = variables
+		}
+		media.Examples = map[string]OpenAPIExample{item.Name: {Summary: item.Name, Value: value}}
+	case "file":
+		mediaType = contentType
+		if mediaType == "" {
+			mediaType = "application/octet-stream"
+		}
+		media.Schema = &OpenAPISchema{Type: "string", Format: "binary"}
+	default:
+		return
+	}
+
+	if operation.RequestBody == nil {
+		operation.RequestBody = &OpenAPIRequestBody{Content: map[string]OpenAPIMediaType{}}
+	}
+
+	existing, ok := operation.RequestBody.Content[mediaType]
+	if !ok {
+		operation.RequestBody.Content[mediaType] = media
+		return
+	}
+
+	// Same operation seen again: keep the first schema and add the example
+	if existing.Schema == nil {
+		existing.Schema = media.Schema
+	}
+	for name, example := range media.Examples {
+		if existing.Examples == nil {
+			existing.Examples = map[string]OpenAPIExample{}
+		}
+		if _, taken := existing.Examples[name]; !taken {
+			existing.Examples[name] = example
+		}
+	}
+	operation.RequestBody.Content[mediaType] = existing
+}
+
+// addSecurityScheme registers a security scheme for the auth and returns
+// its name, or "" when the request is unauthenticated.
+func (b *openAPIBuilder) addSecurityScheme(auth *PostmanAuth) string {
+	if auth == nil {
+		return ""
+	}
+
+	var name string
+	var scheme OpenAPISecurityScheme
+	switch auth.Type {
+	case "bearer":
+		name, scheme = "bearerAuth", OpenAPISecurityScheme{Type: "http", Scheme: "bearer"}
+	case "basic":
+		name, scheme = "basicAuth", OpenAPISecurityScheme{Type: "http", Scheme: "basic"}
+	case "apikey":
+		in := postmanAuthValue(auth.APIKey, "in")
+		if in == "" {
+			in = "header"
+		}
+		keyName := postmanAuthValue(auth.APIKey, "key")
+		if keyName == "" {
+			keyName = "X-API-Key"
+		}
+		name = "apiKeyAuth"
+		if existing, ok := b.securitySchemes()[name]; ok && (existing.Name != keyName || existing.In != in) {
+			name = "apiKeyAuth_" + operationIDWord(keyName)
+		}
+		scheme = OpenAPISecurityScheme{Type: "apiKey", In: in, Name: keyName}
+	default:
+		return ""
+	}
+
+	b.securitySchemes()[name] = scheme
+	return name
+}
+
+func (b *openAPIBuilder) securitySchemes() map[string]OpenAPISecurityScheme {
+	if b.document.Components == nil {
+		b.document.Components = &OpenAPIComponents{SecuritySchemes: map[string]OpenAPISecurityScheme{}}
+	}
+	return b.document.Components.SecuritySchemes
+}
+
+// uniqueOperationID derives a camelCase operationId from the request name,
+// falling back to method and path, and de-duplicates it.
+func (b *openAPIBuilder) uniqueOperationID(name, method, path string) string {
+	base := operationIDWord(name)
+	if base == "" {
+		base = operationIDWord(method + " " + path)
+	}
+
+	id := base
+	for i := 2; b.operationIDs[id]; i++ {
+		id = fmt.Sprintf("%s%d", base, i)
+	}
+	b.operationIDs[id] = true
+	return id
+}
+
+// operationIDWord turns arbitrary text into a camelCase identifier.
+func operationIDWord(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for i, word := range words {
+		runes := []rune(strings.ToLower(word))
+		if i > 0 {
+			runes[0] = unicode.ToUpper(runes[0])
+		}
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
+
+// addOpenAPIParameter adds a parameter unless one with the same name and
+// location already exists.
+func addOpenAPIParameter(operation *OpenAPIOperation, param OpenAPIParameter) {
+	for _, existing := range operation.Parameters {
+		if existing.In == param.In && strings.EqualFold(existing.Name, param.Name) {
+			return
+		}
+	}
+	operation.Parameters = append(operation.Parameters, param)
+}
+
+// exampleValue returns value as an example, omitting empty strings.
+func exampleValue(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
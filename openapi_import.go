@@ -0,0 +1,644 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// openAPIMethods lists the HTTP methods of a path item in output order.
+var openAPIMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// maxExampleDepth bounds example synthesis for deeply nested or recursive schemas.
+const maxExampleDepth = 8
+
+// parseOpenAPIDocument parses an OpenAPI 3.x or Swagger 2.0 document in JSON
+// or YAML. It returns nil if the data is not such a document.
+func parseOpenAPIDocument(data []byte) map[string]any {
+	var document map[string]any
+	if err := json.Unmarshal(data, &document); err != nil {
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return nil
+		}
+	}
+
+	if _, ok := document["openapi"].(string); ok {
+		return document
+	}
+	if version, ok := document["swagger"].(string); ok && strings.HasPrefix(version, "2") {
+		return document
+	}
+	return nil
+}
+
+// openAPIImporter holds the document being imported, for $ref resolution.
+type openAPIImporter struct {
+	document  map[string]any
+	swagger2  bool
+	variables map[string]string
+	warned    map[string]bool
+	visiting  map[string]bool // $refs being expanded, to stop recursive schemas
+}
+
+// convertOpenAPIToPostman builds a Postman collection from an OpenAPI 3.x or
+// Swagger 2.0 document. Operations are grouped into folders by their first
+// tag, and the server URL becomes a {{baseUrl}} collection variable.
+func convertOpenAPIToPostman(document map[string]any, sourcePath string) PostmanCollection {
+	im := &openAPIImporter{
+		document:  document,
+		swagger2:  document["swagger"] != nil,
+		variables: map[string]string{},
+		warned:    map[string]bool{},
+		visiting:  map[string]bool{},
+	}
+
+	info := asMap(document["info"])
+	name := asString(info["title"])
+	if name == "" {
+		name = "Imported API"
+	}
+
+	collection := PostmanCollection{
+		Info: PostmanInfo{
+			PostmanID:   generatePostmanID("openapi", sourceNames([]string{sourcePath})[0], name),
+			Name:        name,
+			Description: asString(info["description"]),
+			Schema:      "https://schema.getpostman.com/json/collection/v2.1.0/collection.json",
+		},
+		Item: []PostmanItem{},
+	}
+
+	im.variables["baseUrl"] = im.baseURL()
+
+	// Global security applies to every operation unless overridden
+	if auth := im.convertSecurity(document["security"]); auth != nil && auth.Type != "noauth" {
+		collection.Auth = auth
+	}
+
+	folders := map[string]int{}
+	paths := asMap(document["paths"])
+	for _, path := range sortedKeys(paths) {
+		pathItem := im.resolve(paths[path])
+		for _, method := range openAPIMethods {
+			operation := asMap(pathItem[method])
+			if operation == nil {
+				continue
+			}
+
+			item := im.convertOperation(path, method, pathItem, operation)
+
+			tags := asSlice(operation["tags"])
+			if len(tags) == 0 {
+				collection.Item = append(collection.Item, item)
+				continue
+			}
+
+			tag := asString(tags[0])
+			index, ok := folders[tag]
+			if !ok {
+				index = len(collection.Item)
+				folders[tag] = index
+				collection.Item = append(collection.Item, PostmanItem{Name: tag, Item: []PostmanItem{}})
+			}
+			collection.Item[index].Item = append(collection.Item[index].Item, item)
+		}
+	}
+
+	for _, key := range sortedKeys(im.variables) {
+		collection.Variable = append(collection.Variable, PostmanVariable{
+			ID:    newID("variable", key),
+			Key:   key,
+			Value: im.variables[key],
+			Type:  "string",
+		})
+	}
+
+	return collection
+}
+
+// baseURL returns the first server URL (OpenAPI 3) or scheme://host/basePath
+// (Swagger 2), with server variables replaced by their defaults.
+func (im *openAPIImporter) baseURL() string {
+	if im.swagger2 {
+		host := asString(im.document["host"])
+		basePath := strings.TrimSuffix(asString(im.document["basePath"]), "/")
+		if host == "" {
+			return basePath
+		}
+		scheme := "https"
+		if schemes := asSlice(im.document["schemes"]); len(schemes) > 0 {
+			scheme = asString(schemes[0])
+		}
+		return scheme + "://" + host + basePath
+	}
+
+	servers := asSlice(im.document["servers"])
+	if len(servers) == 0 {
+		return ""
+	}
+	server := asMap(servers[0])
+	serverURL := asString(server["url"])
+	for name, variable := range asMap(server["variables"]) {
+		value := ""
+		if defaultValue, ok := asMap(variable)["default"]; ok && defaultValue != nil {
+			value = fmt.Sprint(defaultValue)
+		}
+		serverURL = strings.ReplaceAll(serverURL, "{"+name+"}", value)
+	}
+	return strings.TrimSuffix(serverURL, "/")
+}
+
+var openAPIPathParamRegex = regexp.MustCompile(`\{([^}]+)\}`)
+
+func (im *openAPIImporter) convertOperation(path, method string, pathItem, operation map[string]any) PostmanItem {
+	name := asString(operation["summary"])
+	if name == "" {
+		name = asString(operation["operationId"])
+	}
+	if name == "" {
+		name = strings.ToUpper(method) + " " + path
+	}
+
+	// Path-level parameters apply unless the operation overrides them
+	params := map[string]map[string]any{}
+	var order []string
+	for _, list := range []any{pathItem["parameters"], operation["parameters"]} {
+		for _, raw := range asSlice(list) {
+			param := im.resolve(raw)
+			key := asString(param["in"]) + ":" + asString(param["name"])
+			if _, exists := params[key]; !exists {
+				order = append(order, key)
+			}
+			params[key] = param
+		}
+	}
+
+	postmanPath := openAPIPathParamRegex.ReplaceAllString(path, ":$1")
+	req := &PostmanRequest{
+		Method: strings.ToUpper(method),
+		URL:    convertURL("{{baseUrl}}" + postmanPath),
+	}
+
+	var bodyParam map[string]any
+	var formParams []map[string]any
+	for _, key := range order {
+		param := params[key]
+		paramName := asString(param["name"])
+		example := im.parameterExample(param)
+
+		switch asString(param["in"]) {
+		case "path":
+			req.URL.Variable = append(req.URL.Variable, PostmanURLVariable{
+				Key:         paramName,
+				Value:       example,
+				Description: asString(param["description"]),
+			})
+		case "query":
+			required, _ := param["required"].(bool)
+			value := escapeOutsideVariables(example)
+			req.URL.Query = append(req.URL.Query, PostmanQueryParam{
+				Key:      url.QueryEscape(paramName),
+				Value:    value,
+				Disabled: !required,
+			})
+			if required {
+				req.URL.Raw = appendRawQuery(req.URL.Raw, url.QueryEscape(paramName)+"="+value)
+			}
+		case "header":
+			required, _ := param["required"].(bool)
+			req.Header = append(req.Header, PostmanHeader{
+				Key:      paramName,
+				Value:    example,
+				Type:     "text",
+				Disabled: !required,
+			})
+		case "body":
+			bodyParam = param
+		case "formData":
+			formParams = append(formParams, param)
+		}
+	}
+
+	var contentType string
+	if im.swagger2 {
+		req.Body, contentType = im.convertSwagger2Body(operation, bodyParam, formParams)
+	} else {
+		req.Body, contentType = im.convertRequestBody(operation["requestBody"])
+	}
+	if req.Body != nil && contentType != "" {
+		req.Header = append(req.Header, PostmanHeader{Key: "Content-Type", Value: contentType, Type: "text"})
+	}
+
+	if security, ok := operation["security"]; ok {
+		req.Auth = im.convertSecurity(security)
+	}
+
+	return PostmanItem{Name: name, Request: req}
+}
+
+// parameterExample picks an example value for a parameter from its
+// example, examples, default or schema.
+func (im *openAPIImporter) parameterExample(param map[string]any) string {
+	if example, ok := param["example"]; ok {
+		return exampleString(example)
+	}
+	examples := asMap(param["examples"])
+	for _, key := range sortedKeys(examples) {
+		if value, ok := im.resolve(examples[key])["value"]; ok {
+			return exampleString(value)
+		}
+	}
+
+	schema := param["schema"]
+	if im.swagger2 && schema == nil {
+		// Swagger 2 puts non-body schemas on the parameter itself
+		schema = param
+	}
+	return exampleString(im.exampleFromSchema(schema, 0))
+}
+
+// convertRequestBody converts an OpenAPI 3 requestBody, preferring JSON.
+func (im *openAPIImporter) convertRequestBody(raw any) (*PostmanBody, string) {
+	requestBody := im.resolve(raw)
+	content := asMap(requestBody["content"])
+	if len(content) == 0 {
+		return nil, ""
+	}
+
+	mediaTypes := sortedKeys(content)
+	chosen := mediaTypes[0]
+	for _, preferred := range []string{"application/json", "application/x-www-form-urlencoded", "multipart/form-data"} {
+		if _, ok := content[preferred]; ok {
+			chosen = preferred
+			break
+		}
+	}
+	media := asMap(content[chosen])
+
+	switch chosen {
+	case "application/x-www-form-urlencoded", "multipart/form-data":
+		return im.formBody(chosen, im.resolve(media["schema"])), chosen
+	}
+
+	var example any
+	if value, ok := media["example"]; ok {
+		example = value
+	} else if examples := asMap(media["examples"]); len(examples) > 0 {
+		example = im.resolve(examples[sortedKeys(examples)[0]])["value"]
+	} else {
+		example = im.exampleFromSchema(media["schema"], 0)
+	}
+
+	return rawExampleBody(chosen, example), chosen
+}
+
+// convertSwagger2Body converts a Swagger 2 body or formData parameters.
+func (im *openAPIImporter) convertSwagger2Body(operation, bodyParam map[string]any, formParams []map[string]any) (*PostmanBody, string) {
+	consumes := asSlice(operation["consumes"])
+	if len(consumes) == 0 {
+		consumes = asSlice(im.document["consumes"])
+	}
+
+	if len(formParams) > 0 {
+		contentType := "application/x-www-form-urlencoded"
+		for _, c := range consumes {
+			if asString(c) == "multipart/form-data" {
+				contentType = "multipart/form-data"
+			}
+		}
+		for _, param := range formParams {
+			if asString(param["type"]) == "file" {
+				contentType = "multipart/form-data"
+			}
+		}
+
+		schema := map[string]any{"type": "object", "properties": map[string]any{}}
+		for _, param := range formParams {
+			property := map[string]any{"type": param["type"]}
+			if example, ok := param["x-example"]; ok {
+				property["example"] = example
+			}
+			if asString(param["type"]) == "file" {
+				property = map[string]any{"type": "string", "format": "binary"}
+			}
+			schema["properties"].(map[string]any)[asString(param["name"])] = property
+		}
+		return im.formBody(contentType, schema), contentType
+	}
+
+	if bodyParam == nil {
+		return nil, ""
+	}
+
+	contentType := "application/json"
+	if len(consumes) > 0 {
+		contentType = asString(consumes[0])
+	}
+	return rawExampleBody(contentType, im.exampleFromSchema(bodyParam["schema"], 0)), contentType
+}
+
+// formBody builds a urlencoded or formdata body from an object schema.
+func (im *openAPIImporter) formBody(contentType string, schema map[string]any) *PostmanBody {
+	properties := asMap(schema["properties"])
+
+	if contentType == "multipart/form-data" {
+		body := &PostmanBody{Mode: "formdata"}
+		for _, key := range sortedKeys(properties) {
+			property := im.resolve(properties[key])
+			if asString(property["format"]) == "binary" {
+				body.FormData = append(body.FormData, PostmanFormDataParam{Key: key, Type: "file"})
+				continue
+			}
+			body.FormData = append(body.FormData, PostmanFormDataParam{
+				Key:   key,
+				Value: exampleString(im.exampleFromSchema(property, 0)),
+				Type:  "text",
+			})
+		}
+		return body
+	}
+
+	body := &PostmanBody{Mode: "urlencoded"}
+	for _, key := range sortedKeys(properties) {
+		body.URLEncoded = append(body.URLEncoded, PostmanURLEncodedParam{
+			Key:   key,
+			Value: exampleString(im.exampleFromSchema(properties[key], 0)),
+			Type:  "text",
+		})
+	}
+	return body
+}
+
+// rawExampleBody renders an example as a raw body, pretty-printing JSON.
+func rawExampleBody(contentType string, example any) *PostmanBody {
+	if example == nil {
+		return nil
+	}
+
+	if !jsonContentTypeRegex.MatchString(contentType) {
+		return &PostmanBody{Mode: "raw", Raw: exampleString(example)}
+	}
+
+	data, err := json.MarshalIndent(example, "", "  ")
+	if err != nil {
+		return &PostmanBody{Mode: "raw", Raw: exampleString(example)}
+	}
+	return &PostmanBody{
+		Mode:    "raw",
+		Raw:     string(data),
+		Options: &PostmanBodyOptions{Raw: PostmanBodyRaw{Language: "json"}},
+	}
+}
+
+// exampleFromSchema synthesizes an example value from a JSON schema.
+// Recursive references are cut off, returning nil.
+func (im *openAPIImporter) exampleFromSchema(raw any, depth int) any {
+	if ref, ok := asMap(raw)["$ref"].(string); ok {
+		if im.visiting[ref] {
+			return nil
+		}
+		im.visiting[ref] = true
+		defer delete(im.visiting, ref)
+	}
+
+	schema := im.resolve(raw)
+	if schema == nil || depth > maxExampleDepth {
+		return nil
+	}
+
+	if example, ok := schema["example"]; ok {
+		return example
+	}
+	if examples := asSlice(schema["examples"]); len(examples) > 0 {
+		return examples[0]
+	}
+	if value, ok := schema["default"]; ok {
+		return value
+	}
+	if enum := asSlice(schema["enum"]); len(enum) > 0 {
+		return enum[0]
+	}
+
+	if allOf := asSlice(schema["allOf"]); len(allOf) > 0 {
+		merged := map[string]any{}
+		for _, part := range allOf {
+			if object, ok := im.exampleFromSchema(part, depth+1).(map[string]any); ok {
+				for k, v := range object {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if options := asSlice(schema[key]); len(options) > 0 {
+			return im.exampleFromSchema(options[0], depth+1)
+		}
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		object := map[string]any{}
+		properties := asMap(schema["properties"])
+		for _, key := range sortedKeys(properties) {
+			if value := im.exampleFromSchema(properties[key], depth+1); value != nil {
+				object[key] = value
+			}
+		}
+		return object
+	case "array":
+		item := im.exampleFromSchema(schema["items"], depth+1)
+		if item == nil {
+			return []any{}
+		}
+		return []any{item}
+	case "integer":
+		return 0
+	case "number":
+		return 0.0
+	case "boolean":
+		return true
+	case "string":
+		switch asString(schema["format"]) {
+		case "date-time":
+			return "2024-01-01T00:00:00Z"
+		case "date":
+			return "2024-01-01"
+		case "email":
+			return "user@example.com"
+		case "uuid":
+			return "00000000-0000-0000-0000-000000000000"
+		case "uri", "url":
+			return "https://example.com"
+		}
+		return "string"
+	}
+	return nil
+}
+
+// schemaType returns a schema's type, inferring "object" from properties
+// and picking the first non-null type from OpenAPI 3.1 type arrays.
+func schemaType(schema map[string]any) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []any:
+		for _, v := range t {
+			if s := asString(v); s != "null" {
+				return s
+			}
+		}
+	}
+	if schema["properties"] != nil {
+		return "object"
+	}
+	return ""
+}
+
+// convertSecurity maps the first security requirement to Postman auth.
+// An explicitly empty requirement list means no auth.
+func (im *openAPIImporter) convertSecurity(raw any) *PostmanAuth {
+	requirements, ok := raw.([]any)
+	if !ok {
+		return nil
+	}
+	if len(requirements) == 0 {
+		return &PostmanAuth{Type: "noauth"}
+	}
+
+	var schemes map[string]any
+	if im.swagger2 {
+		schemes = asMap(im.document["securityDefinitions"])
+	} else {
+		schemes = asMap(asMap(im.document["components"])["securitySchemes"])
+	}
+
+	for _, requirement := range requirements {
+		names := sortedKeys(asMap(requirement))
+		if len(names) == 0 {
+			// An empty requirement ({}) makes auth optional
+			return &PostmanAuth{Type: "noauth"}
+		}
+		scheme := im.resolve(schemes[names[0]])
+		if auth := im.convertSecurityScheme(scheme); auth != nil {
+			return auth
+		}
+	}
+	return nil
+}
+
+func (im *openAPIImporter) convertSecurityScheme(scheme map[string]any) *PostmanAuth {
+	schemeType := asString(scheme["type"])
+	httpScheme := strings.ToLower(asString(scheme["scheme"]))
+
+	switch {
+	case schemeType == "http" && httpScheme == "bearer", schemeType == "oauth2", schemeType == "openIdConnect":
+		im.variables["bearerToken"] = ""
+		return &PostmanAuth{
+			Type:   "bearer",
+			Bearer: []PostmanAuthBearer{{Key: "token", Value: "{{bearerToken}}", Type: "string"}},
+		}
+	case schemeType == "http" && httpScheme == "basic", schemeType == "basic":
+		im.variables["username"] = ""
+		im.variables["password"] = ""
+		return &PostmanAuth{
+			Type: "basic",
+			Basic: []PostmanAuthBasic{
+				{Key: "username", Value: "{{username}}", Type: "string"},
+				{Key: "password", Value: "{{password}}", Type: "string"},
+			},
+		}
+	case schemeType == "apiKey":
+		im.variables["apiKey"] = ""
+		in := asString(scheme["in"])
+		if in != "query" {
+			in = "header"
+		}
+		return &PostmanAuth{
+			Type: "apikey",
+			APIKey: []PostmanAuthAPIKey{
+				{Key: "key", Value: asString(scheme["name"]), Type: "string"},
+				{Key: "value", Value: "{{apiKey}}", Type: "string"},
+				{Key: "in", Value: in, Type: "string"},
+			},
+		}
+	}
+
+	if schemeType != "" && !im.warned[schemeType+httpScheme] {
+		im.warned[schemeType+httpScheme] = true
+		log.Printf("Warning: security scheme %q is not supported; requests using it have no auth.", strings.TrimSpace(schemeType+" "+httpScheme))
+	}
+	return nil
+}
+
+// resolve follows local $ref pointers (e.g. #/components/schemas/User) and
+// returns the referenced object. External references are not supported.
+func (im *openAPIImporter) resolve(raw any) map[string]any {
+	node := asMap(raw)
+	for seen := 0; node != nil && seen < 32; seen++ {
+		ref, ok := node["$ref"].(string)
+		if !ok {
+			return node
+		}
+		if !strings.HasPrefix(ref, "#/") {
+			if !im.warned[ref] {
+				im.warned[ref] = true
+				log.Printf("Warning: external reference %q is not supported.", ref)
+			}
+			return nil
+		}
+
+		var target any = im.document
+		for _, token := range strings.Split(ref[2:], "/") {
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			target = asMap(target)[token]
+		}
+		node = asMap(target)
+	}
+	return node
+}
+
+// exampleString renders an example value as text for URLs, headers and fields.
+func exampleString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]any, []any:
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func asMap(value any) map[string]any {
+	m, _ := value.(map[string]any)
+	return m
+}
+
+func asSlice(value any) []any {
+	s, _ := value.([]any)
+	return s
+}
+
+func asString(value any) string {
+	s, _ := value.(string)
+	return s
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
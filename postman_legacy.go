@@ -0,0 +1,644 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+)
+
+// Postman collection v1 structure. Requests are a flat list placed into
+// folders through order lists; only the fields used for conversion are
+// declared.
+type PostmanV1Collection struct {
+	ID           string             `json:"id"`
+	Name         string             `json:"name"`
+	Description  any                `json:"description"`
+	Order        []string           `json:"order"`
+	FoldersOrder []string           `json:"folders_order"`
+	Folders      []PostmanV1Folder  `json:"folders"`
+	Requests     []PostmanV1Request `json:"requests"`
+	Variables    []PostmanV1Pair    `json:"variables"`
+	Auth         json.RawMessage    `json:"auth"`
+	Events       []any              `json:"events"`
+}
+
+type PostmanV1Folder struct {
+	ID           string          `json:"id"`
+	Name         string          `json:"name"`
+	Order        []string        `json:"order"`
+	FoldersOrder []string        `json:"folders_order"`
+	Auth         json.RawMessage `json:"auth"`
+	Events       []any           `json:"events"`
+}
+
+type PostmanV1Request struct {
+	ID               string              `json:"id"`
+	Name             string              `json:"name"`
+	Folder           string              `json:"folder"`
+	Method           string              `json:"method"`
+	URL              string              `json:"url"`
+	Headers          string              `json:"headers"`
+	HeaderData       []PostmanV1Pair     `json:"headerData"`
+	QueryParams      []PostmanV1Pair     `json:"queryParams"`
+	PathVariables    map[string]any      `json:"pathVariables"`
+	PathVariableData []PostmanV1Pair     `json:"pathVariableData"`
+	DataMode         string              `json:"dataMode"`
+	DataDisabled     bool                `json:"dataDisabled"`
+	Data             []PostmanV1Pair     `json:"data"`
+	RawModeData      string              `json:"rawModeData"`
+	GraphQLModeData  *PostmanGraphQL     `json:"graphqlModeData"`
+	DataOptions      *PostmanBodyOptions `json:"dataOptions"`
+	Auth             json.RawMessage     `json:"auth"`
+	CurrentHelper    string              `json:"currentHelper"`
+	HelperAttributes map[string]any      `json:"helperAttributes"`
+	PreRequestScript string              `json:"preRequestScript"`
+	Tests            string              `json:"tests"`
+	Events           []any               `json:"events"`
+	Responses        []PostmanV1Response `json:"responses"`
+}
+
+// PostmanV1Pair is a header, query parameter, body field, path variable or
+// collection variable. Values may be any JSON scalar.
+type PostmanV1Pair struct {
+	Key      string `json:"key"`
+	Value    any    `json:"value"`
+	Type     string `json:"type"`
+	Enabled  *bool  `json:"enabled"`
+	Disabled bool   `json:"disabled"`
+}
+
+type PostmanV1Response struct {
+	Name         string `json:"name"`
+	Code         int    `json:"code"`
+	Status       string `json:"status"`
+	ResponseCode struct {
+		Code int    `json:"code"`
+		Name string `json:"name"`
+	} `json:"responseCode"`
+	Headers  []PostmanV1Pair `json:"headers"`
+	Text     string          `json:"text"`
+	Language string          `json:"language"`
+}
+
+func (p PostmanV1Pair) disabled() bool {
+	return p.Disabled || (p.Enabled != nil && !*p.Enabled)
+}
+
+// postmanSchemaVersionRegex extracts the major and minor version from a
+// collection schema URL.
+var postmanSchemaVersionRegex = regexp.MustCompile(`/collection/v(\d+)\.(\d+)`)
+
+// postmanCollectionVersion reports the schema version of a Postman
+// collection: "v1", "v2.0" or "v2.1". It returns "" if data is not a
+// Postman collection. v1 exports have no schema URL and are recognised by
+// their flat requests list and top-level order.
+func postmanCollectionVersion(data []byte) string {
+	var probe struct {
+		Info *struct {
+			Schema string `json:"schema"`
+		} `json:"info"`
+		Requests []json.RawMessage `json:"requests"`
+		Order    []string          `json:"order"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return ""
+	}
+
+	switch {
+	case probe.Info != nil && probe.Info.Schema != "":
+		match := postmanSchemaVersionRegex.FindStringSubmatch(probe.Info.Schema)
+		switch {
+		case match == nil:
+			// Unknown schema URLs are read as the current version
+			return "v2.1"
+		case match[1] == "1":
+			return "v1"
+		case match[1] == "2" && match[2] == "0":
+			return "v2.0"
+		}
+		return "v2.1"
+	case probe.Requests != nil && probe.Order != nil:
+		return "v1"
+	}
+	return ""
+}
+
+// parsePostmanCollection parses a Postman collection, upgrading v1 and v2.0
+// collections to v2.1. Anything the upgrade cannot map is reported as a
+// warning.
+func parsePostmanCollection(data []byte) (PostmanCollection, error) {
+	version := postmanCollectionVersion(data)
+	upgrader := &postmanUpgrader{version: version, names: map[string][]string{}}
+
+	if version == "v1" {
+		var legacy PostmanV1Collection
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return PostmanCollection{}, fmt.Errorf("parsing Postman v1 collection: %w", err)
+		}
+		collection := upgrader.upgradeV1(legacy)
+		upgrader.report()
+		return collection, nil
+	}
+
+	// v2.0 and v2.1 share a structure, except that v2.0 writes auth
+	// attributes as objects. Both allow URLs, headers and descriptions in
+	// shorthand forms, which are expanded first.
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document map[string]any
+	if err := decoder.Decode(&document); err != nil {
+		return PostmanCollection{}, fmt.Errorf("parsing Postman collection: %w", err)
+	}
+	name := ""
+	if info, ok := document["info"].(map[string]any); ok {
+		name, _ = info["name"].(string)
+	}
+	upgrader.normalize(document, name)
+
+	normalized, err := json.Marshal(document)
+	if err != nil {
+		return PostmanCollection{}, fmt.Errorf("parsing Postman collection: %w", err)
+	}
+	var collection PostmanCollection
+	if err := json.Unmarshal(normalized, &collection); err != nil {
+		return PostmanCollection{}, fmt.Errorf("parsing Postman collection: %w", err)
+	}
+	if version == "v2.0" {
+		collection.Info.Schema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+	}
+	upgrader.report()
+	return collection, nil
+}
+
+// postmanUpgrader converts legacy collections and collects what could not
+// be mapped, as messages with the names of the items they affect.
+type postmanUpgrader struct {
+	version  string
+	messages []string
+	names    map[string][]string
+}
+
+// unmapped records that something in the named item could not be mapped.
+// Nothing is recorded for v2.1 collections, which are not upgraded.
+func (u *postmanUpgrader) unmapped(message, name string) {
+	if u.version == "v2.1" {
+		return
+	}
+	if _, seen := u.names[message]; !seen {
+		u.messages = append(u.messages, message)
+	}
+	for _, existing := range u.names[message] {
+		if existing == name {
+			return
+		}
+	}
+	u.names[message] = append(u.names[message], name)
+}
+
+// report logs one warning per unmapped feature, naming up to five items.
+func (u *postmanUpgrader) report() {
+	for _, message := range u.messages {
+		names := u.names[message]
+		if len(names) > 5 {
+			names = append(names[:5:5], fmt.Sprintf("and %d more", len(names)-5))
+		}
+		log.Printf("Warning: upgrading Postman %s collection: %s (%s)", u.version, message, strings.Join(names, ", "))
+	}
+}
+
+// normalize rewrites a decoded v2.x collection in place to the structure
+// PostmanCollection expects. name is the closest enclosing item name.
+func (u *postmanUpgrader) normalize(node any, name string) {
+	switch node := node.(type) {
+	case []any:
+		for _, child := range node {
+			u.normalize(child, name)
+		}
+	case map[string]any:
+		if itemName, ok := node["name"].(string); ok && (node["request"] != nil || node["item"] != nil) {
+			name = itemName
+		}
+		// Key/value pairs (variables, headers, auth attributes) may hold
+		// numbers or booleans
+		if _, isPair := node["key"].(string); isPair {
+			if value, ok := node["value"]; ok && value != nil {
+				node["value"] = postmanValueString(value)
+			}
+		}
+
+		for key, value := range node {
+			switch key {
+			case "request":
+				if raw, ok := value.(string); ok {
+					node[key] = map[string]any{"method": "GET", "url": convertURL(raw)}
+					continue
+				}
+			case "url":
+				if raw, ok := value.(string); ok {
+					node[key] = convertURL(raw)
+					continue
+				}
+			case "header":
+				if raw, ok := value.(string); ok {
+					node[key] = parsePostmanHeaderString(raw)
+					continue
+				}
+			case "description":
+				node[key] = postmanDescription(value)
+				continue
+			case "src":
+				// Multipart file fields may list several files
+				if files, ok := value.([]any); ok {
+					if len(files) > 1 {
+						u.unmapped("only the first file of multi-file form fields was kept", name)
+					}
+					node[key] = nil
+					if len(files) > 0 {
+						node[key] = postmanValueString(files[0])
+					}
+					continue
+				}
+			case "event":
+				if events, ok := value.([]any); ok && len(events) > 0 {
+					u.unmapped("scripts are not supported and were dropped", name)
+				}
+				continue
+			case "auth":
+				if auth, ok := value.(map[string]any); ok {
+					node[key] = u.upgradeAuth(auth, name)
+					u.normalize(node[key], name)
+					continue
+				}
+			}
+			u.normalize(value, name)
+		}
+	}
+}
+
+// upgradeAuth converts auth attributes written as an object (v2.0) to the
+// key/value list used by v1 and v2.1. Auth types other than bearer, basic
+// and API key keep their type but lose their settings, and are reported.
+func (u *postmanUpgrader) upgradeAuth(auth map[string]any, name string) map[string]any {
+	authType, _ := auth["type"].(string)
+	switch authType {
+	case "", "noauth", "bearer", "basic", "apikey":
+	default:
+		u.unmapped(fmt.Sprintf("%s auth is not supported and was dropped", authType), name)
+	}
+
+	attributes, ok := auth[authType].(map[string]any)
+	if !ok {
+		return auth
+	}
+	var list []any
+	for _, key := range sortedKeys(attributes) {
+		list = append(list, map[string]any{"key": key, "value": postmanValueString(attributes[key]), "type": "string"})
+	}
+	upgraded := map[string]any{"type": authType}
+	upgraded[authType] = list
+	return upgraded
+}
+
+// legacyAuth converts the auth of a v1 collection, folder or request.
+func (u *postmanUpgrader) legacyAuth(raw json.RawMessage, name string) *PostmanAuth {
+	var auth map[string]any
+	if err := json.Unmarshal(raw, &auth); err != nil || auth == nil {
+		return nil
+	}
+	u.normalize(auth, name)
+	data, err := json.Marshal(u.upgradeAuth(auth, name))
+	if err != nil {
+		return nil
+	}
+	var upgraded PostmanAuth
+	if err := json.Unmarshal(data, &upgraded); err != nil {
+		return nil
+	}
+	return &upgraded
+}
+
+// postmanV1HelperTypes maps the auth helpers of old v1 requests to auth
+// types.
+var postmanV1HelperTypes = map[string]string{
+	"basicAuth":  "basic",
+	"bearerAuth": "bearer",
+	"digestAuth": "digest",
+	"oAuth1":     "oauth1",
+	"oAuth2":     "oauth2",
+	"hawkAuth":   "hawk",
+	"awsSigV4":   "awsv4",
+	"ntlmAuth":   "ntlm",
+}
+
+// upgradeV1 converts a v1 collection. Folders come before requests at each
+// level, as in Postman. Requests and folders missing from every order list
+// are kept: in their folder if they name one, otherwise at the top level.
+func (u *postmanUpgrader) upgradeV1(legacy PostmanV1Collection) PostmanCollection {
+	collection := PostmanCollection{
+		Info: PostmanInfo{
+			PostmanID:   legacy.ID,
+			Name:        legacy.Name,
+			Description: postmanDescription(legacy.Description),
+			Schema:      "https://schema.getpostman.com/json/collection/v2.1.0/collection.json",
+		},
+		Auth: u.legacyAuth(legacy.Auth, legacy.Name),
+	}
+	if collection.Info.PostmanID == "" {
+		collection.Info.PostmanID = generatePostmanID("postman-v1", legacy.Name)
+	}
+	if len(legacy.Events) > 0 {
+		u.unmapped("scripts are not supported and were dropped", legacy.Name)
+	}
+	for _, v := range legacy.Variables {
+		if v.Key != "" {
+			collection.Variable = append(collection.Variable, PostmanVariable{Key: v.Key, Value: postmanValueString(v.Value), Type: "string"})
+		}
+	}
+
+	folders := make(map[string]PostmanV1Folder)
+	subfolders := make(map[string]bool)
+	for _, folder := range legacy.Folders {
+		folders[folder.ID] = folder
+		for _, id := range folder.FoldersOrder {
+			subfolders[id] = true
+		}
+	}
+	requests := make(map[string]PostmanV1Request)
+	for _, req := range legacy.Requests {
+		requests[req.ID] = req
+	}
+	placedFolders := make(map[string]bool)
+	placedRequests := make(map[string]bool)
+
+	var buildItems func(folderID string, folderOrder, requestOrder []string) []PostmanItem
+	buildItems = func(folderID string, folderOrder, requestOrder []string) []PostmanItem {
+		var items []PostmanItem
+		for _, id := range folderOrder {
+			folder, ok := folders[id]
+			if !ok || placedFolders[id] {
+				continue
+			}
+			placedFolders[id] = true
+			if len(folder.Events) > 0 {
+				u.unmapped("scripts are not supported and were dropped", folder.Name)
+			}
+			items = append(items, PostmanItem{
+				Name: folder.Name,
+				Auth: u.legacyAuth(folder.Auth, folder.Name),
+				Item: buildItems(id, folder.FoldersOrder, folder.Order),
+			})
+		}
+
+		place := func(req PostmanV1Request) {
+			placedRequests[req.ID] = true
+			items = append(items, u.upgradeV1Request(req))
+		}
+		for _, id := range requestOrder {
+			if req, ok := requests[id]; ok && !placedRequests[id] {
+				place(req)
+			}
+		}
+		for _, req := range legacy.Requests {
+			if req.Folder == folderID && !placedRequests[req.ID] {
+				place(req)
+			}
+		}
+		return items
+	}
+
+	rootFolders := legacy.FoldersOrder
+	if rootFolders == nil {
+		for _, folder := range legacy.Folders {
+			if !subfolders[folder.ID] {
+				rootFolders = append(rootFolders, folder.ID)
+			}
+		}
+	}
+	collection.Item = buildItems("", rootFolders, legacy.Order)
+
+	// Folders and requests not reachable from any order list
+	var leftoverFolders []string
+	for _, folder := range legacy.Folders {
+		if !placedFolders[folder.ID] {
+			leftoverFolders = append(leftoverFolders, folder.ID)
+		}
+	}
+	collection.Item = append(collection.Item, buildItems("", leftoverFolders, nil)...)
+	for _, req := range legacy.Requests {
+		if !placedRequests[req.ID] {
+			placedRequests[req.ID] = true
+			collection.Item = append(collection.Item, u.upgradeV1Request(req))
+		}
+	}
+	if collection.Item == nil {
+		collection.Item = []PostmanItem{}
+	}
+
+	return collection
+}
+
+// upgradeV1Request converts a v1 request and its saved responses.
+func (u *postmanUpgrader) upgradeV1Request(req PostmanV1Request) PostmanItem {
+	name := req.Name
+	if name == "" {
+		name = req.URL
+	}
+
+	method := strings.ToUpper(req.Method)
+	if method == "" {
+		method = "GET"
+	}
+	request := &PostmanRequest{
+		Method: method,
+		URL:    convertURL(req.URL),
+		Body:   u.upgradeV1Body(req, name),
+	}
+
+	if len(req.HeaderData) > 0 {
+		for _, header := range req.HeaderData {
+			request.Header = append(request.Header, PostmanHeader{Key: header.Key, Value: postmanValueString(header.Value), Disabled: header.disabled()})
+		}
+	} else {
+		request.Header = parsePostmanHeaderString(req.Headers)
+	}
+
+	// The URL only carries enabled query parameters
+	for _, param := range req.QueryParams {
+		if param.disabled() {
+			request.URL.Query = append(request.URL.Query, PostmanQueryParam{Key: param.Key, Value: postmanValueString(param.Value), Disabled: true})
+		}
+	}
+
+	if len(req.PathVariableData) > 0 {
+		for _, variable := range req.PathVariableData {
+			request.URL.Variable = append(request.URL.Variable, PostmanURLVariable{Key: variable.Key, Value: postmanValueString(variable.Value)})
+		}
+	} else {
+		for _, key := range sortedKeys(req.PathVariables) {
+			request.URL.Variable = append(request.URL.Variable, PostmanURLVariable{Key: key, Value: postmanValueString(req.PathVariables[key])})
+		}
+	}
+
+	if len(req.Auth) > 0 && string(req.Auth) != "null" {
+		request.Auth = u.legacyAuth(req.Auth, name)
+	} else if authType, ok := postmanV1HelperTypes[req.CurrentHelper]; ok {
+		request.Auth = u.upgradeV1Helper(authType, req.HelperAttributes, name)
+	} else if req.CurrentHelper != "" && req.CurrentHelper != "normal" {
+		u.unmapped(fmt.Sprintf("%s auth is not supported and was dropped", req.CurrentHelper), name)
+	}
+
+	if req.PreRequestScript != "" || req.Tests != "" || len(req.Events) > 0 {
+		u.unmapped("scripts are not supported and were dropped", name)
+	}
+
+	item := PostmanItem{Name: name, Request: request}
+	for _, response := range req.Responses {
+		code, status := response.ResponseCode.Code, response.ResponseCode.Name
+		if code == 0 {
+			code = response.Code
+		}
+		if status == "" {
+			status = response.Status
+		}
+		upgraded := PostmanResponse{
+			Name:            response.Name,
+			OriginalRequest: request,
+			Status:          status,
+			Code:            code,
+			PreviewLanguage: response.Language,
+			Body:            response.Text,
+		}
+		for _, header := range response.Headers {
+			upgraded.Header = append(upgraded.Header, PostmanHeader{Key: header.Key, Value: postmanValueString(header.Value)})
+		}
+		item.Response = append(item.Response, upgraded)
+	}
+	return item
+}
+
+// upgradeV1Helper converts the auth helper settings of an old v1 request.
+func (u *postmanUpgrader) upgradeV1Helper(authType string, attributes map[string]any, name string) *PostmanAuth {
+	value := func(key string) string {
+		return postmanValueString(attributes[key])
+	}
+	switch authType {
+	case "basic":
+		return &PostmanAuth{Type: "basic", Basic: []PostmanAuthBasic{
+			{Key: "username", Value: value("username"), Type: "string"},
+			{Key: "password", Value: value("password"), Type: "string"},
+		}}
+	case "bearer":
+		return &PostmanAuth{Type: "bearer", Bearer: []PostmanAuthBearer{
+			{Key: "token", Value: value("token"), Type: "string"},
+		}}
+	}
+	u.unmapped(fmt.Sprintf("%s auth is not supported and was dropped", authType), name)
+	return &PostmanAuth{Type: authType}
+}
+
+// upgradeV1Body converts the body of a v1 request, selected by its data
+// mode.
+func (u *postmanUpgrader) upgradeV1Body(req PostmanV1Request, name string) *PostmanBody {
+	if req.DataDisabled {
+		return nil
+	}
+
+	switch req.DataMode {
+	case "", "none":
+		return nil
+	case "urlencoded", "params":
+		if len(req.Data) == 0 {
+			return nil
+		}
+	}
+
+	switch req.DataMode {
+	case "raw":
+		if req.RawModeData == "" {
+			return nil
+		}
+		body := &PostmanBody{Mode: "raw", Raw: req.RawModeData}
+		if req.DataOptions != nil && req.DataOptions.Raw.Language != "" {
+			body.Options = req.DataOptions
+		}
+		return body
+	case "urlencoded":
+		body := &PostmanBody{Mode: "urlencoded"}
+		for _, field := range req.Data {
+			body.URLEncoded = append(body.URLEncoded, PostmanURLEncodedParam{Key: field.Key, Value: postmanValueString(field.Value), Type: "text", Disabled: field.disabled()})
+		}
+		return body
+	case "params":
+		body := &PostmanBody{Mode: "formdata"}
+		for _, field := range req.Data {
+			param := PostmanFormDataParam{Key: field.Key, Type: "text", Disabled: field.disabled()}
+			if field.Type == "file" {
+				param.Type = "file"
+				param.Src = postmanValueString(field.Value)
+			} else {
+				param.Value = postmanValueString(field.Value)
+			}
+			body.FormData = append(body.FormData, param)
+		}
+		return body
+	case "graphql":
+		if req.GraphQLModeData == nil {
+			return nil
+		}
+		return &PostmanBody{Mode: "graphql", GraphQL: req.GraphQLModeData}
+	case "binary":
+		u.unmapped("binary bodies have no file path in v1 collections and were dropped", name)
+		return nil
+	}
+	u.unmapped(fmt.Sprintf("%q bodies are not supported and were dropped", req.DataMode), name)
+	return nil
+}
+
+// parsePostmanHeaderString parses headers written as "Key: Value" lines.
+// Lines commented out with // are disabled headers.
+func parsePostmanHeaderString(raw string) []PostmanHeader {
+	var headers []PostmanHeader
+	for _, line := range strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		disabled := strings.HasPrefix(line, "//")
+		line = strings.TrimSpace(strings.TrimPrefix(line, "//"))
+		key, value, found := strings.Cut(line, ":")
+		if !found || strings.TrimSpace(key) == "" {
+			continue
+		}
+		headers = append(headers, PostmanHeader{Key: strings.TrimSpace(key), Value: strings.TrimSpace(value), Disabled: disabled})
+	}
+	return headers
+}
+
+// postmanDescription returns a description written as a string or as a
+// {"content": ...} object.
+func postmanDescription(value any) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case map[string]any:
+		content, _ := value["content"].(string)
+		return content
+	}
+	return ""
+}
+
+// postmanValueString returns a JSON scalar as a string; other values are
+// written as JSON.
+func postmanValueString(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+)
+
+func handleToHTTPieCommand() {
+	fs := flag.NewFlagSet("to-httpie", flag.ExitOnError)
+	args := parseArgs(fs, os.Args[2:])
+
+	if len(args) < 2 {
+		fmt.Println("Usage: postmanzier to-httpie <input-postman-collection> <output-httpie-collection> [<postman-environment> ...]")
+		fmt.Println("Example: postmanzier to-httpie output.postman.json collection.json env/Production.postman_environment.json")
+		os.Exit(1)
+	}
+
+	inputFile := args[0]
+	outputPath := args[1]
+
+	data, err := readInput(inputFile)
+	if err != nil {
+		log.Fatalf("Error reading input file: %v", err)
+	}
+
+	postmanCollection, err := parsePostmanCollection(data)
+	if err != nil {
+		log.Fatalf("Error parsing Postman collection: %v", err)
+	}
+
+	var environments []PostmanEnvironment
+	for _, envFile := range args[2:] {
+		envData, err := readInput(envFile)
+		if err != nil {
+			log.Fatalf("Error reading environment file %s: %v", envFile, err)
+		}
+		var env PostmanEnvironment
+		if err := json.Unmarshal(envData, &env); err != nil {
+			log.Fatalf("Error parsing Postman environment %s: %v", envFile, err)
+		}
+		environments = append(environments, env)
+	}
+
+	httpieWorkspace := convertPostmanToWorkspace(postmanCollection, environments)
+
+	outputData, err := json.MarshalIndent(httpieWorkspace, "", "  ")
+	if err != nil {
+		log.Fatalf("Error marshaling HTTPie collection: %v", err)
+	}
+
+	finalOutputPath := generateUniqueFilename(outputPath)
+	if err := os.WriteFile(finalOutputPath, outputData, 0644); err != nil {
+		log.Fatalf("Error writing output file: %v", err)
+	}
+
+	fmt.Println("Conversion to HTTPie completed!")
+	fmt.Printf("* Total APIs: %d\n", countPostmanRequests(postmanCollection.Item))
+	fmt.Printf("* Total environments: %d\n", len(httpieWorkspace.Environments))
+	fmt.Printf("--> Output file: %s\n", finalOutputPath)
+}
+
+// convertPostmanToWorkspace converts a Postman collection back to an HTTPie
+// workspace. Top-level requests become entry requests and folders become
+// (nested) collections. Collection variables become a default environment,
+// followed by any Postman environments given.
+func convertPostmanToWorkspace(postman PostmanCollection, environments []PostmanEnvironment) HTTPieWorkspace {
+	workspace := HTTPieWorkspace{
+		Meta: HTTPieMeta{
+			Format:      "httpie-workspace-v1",
+			Version:     "1.0.0",
+			ContentType: "application/vnd.httpie.workspace+json",
+			Schema:      "https://httpie.io/schemas/httpie-workspace-v1.json",
+			Docs:        "https://httpie.io/docs/cli/sharing-and-collaboration",
+			Source:      "postmanzier",
+		},
+		Entry: HTTPieEntry{
+			Name: postman.Info.Name,
+			Icon: HTTPieIcon{Name: "folder", Color: "#3498db"},
+			Auth: convertPostmanAuth(postman.Auth, false),
+		},
+	}
+
+	hasAuth := hasEffectiveAuth(postman.Auth, false)
+	for _, item := range postman.Item {
+		if item.Request != nil {
+			workspace.Entry.Requests = append(workspace.Entry.Requests, convertPostmanRequest(item, hasAuth))
+		} else {
+			workspace.Entry.Collections = append(workspace.Entry.Collections, convertPostmanFolder(item, hasAuth))
+		}
+	}
+
+	if len(postman.Variable) > 0 {
+		env := HTTPieEnvironment{
+			Name:      "Collection variables",
+			IsDefault: len(environments) == 0,
+			Variables: []HTTPieEnvironmentVariable{},
+		}
+		for _, v := range postman.Variable {
+			env.Variables = append(env.Variables, HTTPieEnvironmentVariable{
+				Name:     v.Key,
+				Value:    v.Value,
+				IsSecret: v.Type == "secret",
+			})
+		}
+		workspace.Environments = append(workspace.Environments, env)
+	}
+
+	for i, postmanEnv := range environments {
+		env := HTTPieEnvironment{
+			Name:      postmanEnv.Name,
+			IsDefault: i == 0,
+			Variables: []HTTPieEnvironmentVariable{},
+		}
+		for _, v := range postmanEnv.Values {
+			if !v.Enabled {
+				continue
+			}
+			env.Variables = append(env.Variables, HTTPieEnvironmentVariable{
+				Name:     v.Key,
+				Value:    v.Value,
+				IsSecret: v.Type == "secret",
+			})
+		}
+		workspace.Environments = append(workspace.Environments, env)
+	}
+
+	return workspace
+}
+
+// convertPostmanFolder converts a Postman folder into an HTTPie collection,
+// recursing into sub-folders.
+func convertPostmanFolder(folder PostmanItem, parentHasAuth bool) HTTPieCollection {
+	collection := HTTPieCollection{
+		Name:     folder.Name,
+		Icon:     HTTPieIcon{Name: "folder", Color: "#3498db"},
+		Auth:     convertPostmanAuth(folder.Auth, parentHasAuth),
+		Requests: []HTTPieRequest{},
+	}
+
+	hasAuth := hasEffectiveAuth(folder.Auth, parentHasAuth)
+	for _, item := range folder.Item {
+		if item.Request != nil {
+			collection.Requests = append(collection.Requests, convertPostmanRequest(item, hasAuth))
+		} else {
+			collection.Collections = append(collection.Collections, convertPostmanFolder(item, hasAuth))
+		}
+	}
+
+	return collection
+}
+
+// hasEffectiveAuth reports whether requests below a level with the given
+// auth end up with credentials.
+func hasEffectiveAuth(auth *PostmanAuth, parentHasAuth bool) bool {
+	if auth == nil {
+		return parentHasAuth
+	}
+	return auth.Type != "noauth"
+}
+
+func convertPostmanRequest(item PostmanItem, parentHasAuth bool) HTTPieRequest {
+	postmanReq := item.Request
+
+	req := HTTPieRequest{
+		Name:        item.Name,
+		URL:         postmanReq.URL.Raw,
+		Method:      postmanReq.Method,
+		Headers:     []HTTPieHeader{},
+		QueryParams: []HTTPieQueryParam{},
+		PathParams:  []HTTPiePathParam{},
+		Auth:        convertPostmanAuth(postmanReq.Auth, parentHasAuth),
+		Body:        convertPostmanBody(postmanReq.Body, postmanReq.Header),
+	}
+
+	if req.URL == "" {
+		req.URL = postmanURLString(postmanReq.URL)
+	}
+	req.URL = restorePathParams(req.URL, postmanReq.URL.Variable)
+	if req.Method == "" {
+		req.Method = "GET"
+	}
+
+	// Skip the Content-Type convertRequest adds for the body, or every round
+	// trip would turn it into an explicit header
+	impliedType := impliedContentType(req.Body)
+	for _, header := range postmanReq.Header {
+		if impliedType != "" && !header.Disabled && strings.EqualFold(header.Key, "Content-Type") && strings.EqualFold(header.Value, impliedType) {
+			continue
+		}
+		req.Headers = append(req.Headers, HTTPieHeader{
+			Name:    header.Key,
+			Value:   header.Value,
+			Enabled: !header.Disabled,
+		})
+	}
+
+	for _, param := range postmanReq.URL.Query {
+		req.QueryParams = append(req.QueryParams, HTTPieQueryParam{
+			Name:    unescapeQueryComponent(param.Key),
+			Value:   unescapeQueryComponent(param.Value),
+			Enabled: !param.Disabled,
+		})
+	}
+
+	for _, variable := range postmanReq.URL.Variable {
+		req.PathParams = append(req.PathParams, HTTPiePathParam{
+			Name:        variable.Key,
+			Value:       variable.Value,
+			Enabled:     true,
+			Description: variable.Description,
+		})
+	}
+
+	return req
+}
+
+// restorePathParams is the inverse of applyPathParams: it rewrites :name
+// segments of path variables back to HTTPie's {name} form.
+func restorePathParams(rawURL string, variables []PostmanURLVariable) string {
+	for _, variable := range variables {
+		if variable.Key == "" {
+			continue
+		}
+		segmentRegex := regexp.MustCompile(`/:` + regexp.QuoteMeta(variable.Key) + `([/?#]|$)`)
+		rawURL = segmentRegex.ReplaceAllString(rawURL, "/{"+variable.Key+"}$1")
+	}
+	return rawURL
+}
+
+// convertPostmanAuth is the inverse of convertAuth. A missing auth inherits
+// from the parent when there is one.
+func convertPostmanAuth(auth *PostmanAuth, parentHasAuth bool) HTTPieAuth {
+	if auth == nil {
+		if parentHasAuth {
+			return HTTPieAuth{Type: "inherited"}
+		}
+		return HTTPieAuth{Type: "none"}
+	}
+
+	switch auth.Type {
+	case "bearer":
+		return HTTPieAuth{
+			Type:   "bearer",
+			Target: "header",
+			Credentials: HTTPieAuthCredentials{
+				Password: postmanAuthValue(auth.Bearer, "token"),
+			},
+		}
+	case "basic":
+		return HTTPieAuth{
+			Type: "basic",
+			Credentials: HTTPieAuthCredentials{
+				Username: postmanAuthValue(auth.Basic, "username"),
+				Password: postmanAuthValue(auth.Basic, "password"),
+			},
+		}
+	case "apikey":
+		return HTTPieAuth{
+			Type: "apiKey",
+			Credentials: HTTPieAuthCredentials{
+				Username: postmanAuthValue(auth.APIKey, "key"),
+				Password: postmanAuthValue(auth.APIKey, "value"),
+			},
+		}
+	case "noauth":
+		return HTTPieAuth{Type: "none"}
+	default:
+		log.Printf("Warning: Postman auth type %q has no HTTPie equivalent; dropping it.", auth.Type)
+		return HTTPieAuth{Type: "none"}
+	}
+}
+
+// postmanAuthValue looks up a key in a Postman auth attribute list.
+func postmanAuthValue[T PostmanAuthBearer | PostmanAuthBasic | PostmanAuthAPIKey](attributes []T, key string) string {
+	for _, attribute := range attributes {
+		kv := PostmanAuthBearer(attribute)
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return ""
+}
+
+// convertPostmanBody is the inverse of convertBody. Raw bodies take their
+// format from the request's Content-Type header.
+func convertPostmanBody(body *PostmanBody, headers []PostmanHeader) HTTPieBody {
+	httpieBody := HTTPieBody{
+		Type: "none",
+		Form: HTTPieForm{Fields: []HTTPieFormField{}},
+	}
+	if body == nil {
+		return httpieBody
+	}
+
+	switch body.Mode {
+	case "raw":
+		if body.Raw == "" {
+			return httpieBody
+		}
+		httpieBody.Type = "text"
+		httpieBody.Text = HTTPieText{
+			Value:  body.Raw,
+			Format: postmanHeaderValue(headers, "Content-Type"),
+		}
+		if httpieBody.Text.Format == "" && body.Options != nil && body.Options.Raw.Language == "json" {
+			httpieBody.Text.Format = "application/json"
+		}
+	case "urlencoded":
+		httpieBody.Type = "form"
+		for _, param := range body.URLEncoded {
+			httpieBody.Form.Fields = append(httpieBody.Form.Fields, HTTPieFormField{
+				Name:    param.Key,
+				Value:   param.Value,
+				Enabled: !param.Disabled,
+			})
+		}
+	case "formdata":
+		httpieBody.Type = "form"
+		httpieBody.Form.IsMultipart = true
+		for _, param := range body.FormData {
+			field := HTTPieFormField{
+				Name:    param.Key,
+				Value:   param.Value,
+				Enabled: !param.Disabled,
+			}
+			if param.Type == "file" {
+				field.Type = "file"
+				field.Value = param.Src
+			}
+			httpieBody.Form.Fields = append(httpieBody.Form.Fields, field)
+		}
+	case "graphql":
+		if body.GraphQL != nil {
+			httpieBody.Type = "graphql"
+			httpieBody.GraphQL = HTTPieGraphQL{
+				Query:     body.GraphQL.Query,
+				Variables: body.GraphQL.Variables,
+			}
+		}
+	case "file":
+		if body.File != nil {
+			httpieBody.Type = "file"
+			httpieBody.File = HTTPieFile{Name: body.File.Src}
+		}
+	default:
+		log.Printf("Warning: Postman body mode %q has no HTTPie equivalent; dropping it.", body.Mode)
+	}
+
+	return httpieBody
+}
+
+// impliedContentType returns the Content-Type convertRequest adds for an
+// HTTPie body, or "" if it adds none.
+func impliedContentType(body HTTPieBody) string {
+	switch body.Type {
+	case "form":
+		if !body.Form.IsMultipart {
+			return "application/x-www-form-urlencoded"
+		}
+	case "graphql":
+		return "application/json"
+	case "text":
+		return body.Text.Format
+	}
+	return ""
+}
+
+// postmanHeaderValue returns the value of the first enabled header with the
+// given name (case-insensitive).
+func postmanHeaderValue(headers []PostmanHeader, name string) string {
+	for _, header := range headers {
+		if !header.Disabled && strings.EqualFold(header.Key, name) {
+			return header.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// roundTripWorkspace uses every construct convertPostmanToWorkspace can map
+// back, in the shape it produces them, so a Postman round trip must give the
+// same workspace.
+func roundTripWorkspace() HTTPieWorkspace {
+	folderIcon := HTTPieIcon{Name: "folder", Color: "#3498db"}
+	noForm := HTTPieForm{Fields: []HTTPieFormField{}}
+
+	return HTTPieWorkspace{
+		Entry: HTTPieEntry{
+			Name: "Round trip",
+			Icon: folderIcon,
+			Auth: HTTPieAuth{Type: "bearer", Target: "header", Credentials: HTTPieAuthCredentials{Password: "{{token}}"}},
+			Requests: []HTTPieRequest{
+				{
+					Name:   "Get user",
+					URL:    "{{baseUrl}}/orgs/{org}/users/{id}?page=1&sort={{sort}}",
+					Method: "GET",
+					Headers: []HTTPieHeader{
+						{Name: "Accept", Value: "application/json", Enabled: true},
+						{Name: "X-Trace", Value: "{{trace}}", Enabled: false},
+					},
+					QueryParams: []HTTPieQueryParam{
+						{Name: "page", Value: "1", Enabled: true},
+						{Name: "sort", Value: "{{sort}}", Enabled: true},
+						{Name: "debug", Value: "true", Enabled: false},
+					},
+					PathParams: []HTTPiePathParam{
+						{Name: "org", Value: "acme", Enabled: true, Description: "Organization slug"},
+						{Name: "id", Value: "42", Enabled: true},
+					},
+					Auth: HTTPieAuth{Type: "inherited"},
+					Body: HTTPieBody{Type: "none", Form: noForm},
+				},
+			},
+			Collections: []HTTPieCollection{
+				{
+					Name: "Users",
+					Icon: folderIcon,
+					Auth: HTTPieAuth{Type: "basic", Credentials: HTTPieAuthCredentials{Username: "admin", Password: "{{password}}"}},
+					Requests: []HTTPieRequest{
+						{
+							Name:        "Create user",
+							URL:         "{{baseUrl}}/users",
+							Method:      "POST",
+							Headers:     []HTTPieHeader{},
+							QueryParams: []HTTPieQueryParam{},
+							PathParams:  []HTTPiePathParam{},
+							Auth:        HTTPieAuth{Type: "inherited"},
+							Body: HTTPieBody{
+								Type: "text",
+								Text: HTTPieText{Value: `{"name": "Ann"}`, Format: "application/json"},
+								Form: noForm,
+							},
+						},
+						{
+							Name:        "Log in",
+							URL:         "{{baseUrl}}/login",
+							Method:      "POST",
+							Headers:     []HTTPieHeader{{Name: "X-Client", Value: "cli", Enabled: true}},
+							QueryParams: []HTTPieQueryParam{},
+							PathParams:  []HTTPiePathParam{},
+							Auth:        HTTPieAuth{Type: "none"},
+							Body: HTTPieBody{
+								Type: "form",
+								Form: HTTPieForm{Fields: []HTTPieFormField{
+									{Name: "user", Value: "admin", Enabled: true},
+									{Name: "remember", Value: "1", Enabled: false},
+								}},
+							},
+						},
+					},
+					Collections: []HTTPieCollection{
+						{
+							Name: "Avatars",
+							Icon: folderIcon,
+							Auth: HTTPieAuth{Type: "apiKey", Credentials: HTTPieAuthCredentials{Username: "X-API-Key", Password: "{{apiKey}}"}},
+							Requests: []HTTPieRequest{
+								{
+									Name:        "Upload avatar",
+									URL:         "{{baseUrl}}/users/{id}/avatar",
+									Method:      "PUT",
+									Headers:     []HTTPieHeader{},
+									QueryParams: []HTTPieQueryParam{},
+									PathParams:  []HTTPiePathParam{{Name: "id", Value: "42", Enabled: true}},
+									Auth:        HTTPieAuth{Type: "inherited"},
+									Body: HTTPieBody{
+										Type: "form",
+										Form: HTTPieForm{IsMultipart: true, Fields: []HTTPieFormField{
+											{Name: "caption", Value: "Me", Enabled: true},
+											{Name: "file", Value: "avatars/me.png", Enabled: true, Type: "file"},
+										}},
+									},
+								},
+							},
+						},
+					},
+				},
+				{
+					Name: "GraphQL",
+					Icon: folderIcon,
+					Auth: HTTPieAuth{Type: "inherited"},
+					Requests: []HTTPieRequest{
+						{
+							Name:        "Search",
+							URL:         "{{baseUrl}}/graphql",
+							Method:      "POST",
+							Headers:     []HTTPieHeader{},
+							QueryParams: []HTTPieQueryParam{},
+							PathParams:  []HTTPiePathParam{},
+							Auth:        HTTPieAuth{Type: "inherited"},
+							Body: HTTPieBody{
+								Type:    "graphql",
+								GraphQL: HTTPieGraphQL{Query: "query($q: String) { users(q: $q) { id } }", Variables: `{"q": "ann"}`},
+								Form:    noForm,
+							},
+						},
+					},
+				},
+			},
+		},
+		Environments: []HTTPieEnvironment{
+			{
+				Name:      "Production",
+				IsDefault: true,
+				Variables: []HTTPieEnvironmentVariable{
+					{Name: "baseUrl", Value: "https://api.example.com"},
+					{Name: "token", Value: "prod-token", IsSecret: true},
+					{Name: "password", Value: "hunter2", IsSecret: true},
+				},
+			},
+			{
+				Name: "Staging",
+				Variables: []HTTPieEnvironmentVariable{
+					{Name: "baseUrl", Value: "https://staging.example.com"},
+					{Name: "apiKey", Value: "staging-key", IsSecret: true},
+				},
+			},
+		},
+	}
+}
+
+func TestPostmanRoundTrip(t *testing.T) {
+	workspace := roundTripWorkspace()
+
+	collection := convertWorkspaceToPostman(workspace, "workspace.json")
+	var environments []PostmanEnvironment
+	for _, env := range workspace.Environments {
+		environments = append(environments, convertEnvironment(env))
+	}
+
+	// Go through JSON, as the collection would on disk
+	data, err := json.Marshal(collection)
+	if err != nil {
+		t.Fatalf("marshaling collection: %v", err)
+	}
+	parsed, err := parsePostmanCollection(data)
+	if err != nil {
+		t.Fatalf("parsing collection: %v", err)
+	}
+
+	got := convertPostmanToWorkspace(parsed, environments)
+
+	assertJSONEqual(t, "entry", got.Entry, workspace.Entry)
+
+	if len(got.Environments) != len(workspace.Environments)+1 {
+		t.Fatalf("got %d environments, want collection variables plus %d", len(got.Environments), len(workspace.Environments))
+	}
+	assertJSONEqual(t, "environments", got.Environments[1:], workspace.Environments)
+
+	wantVariables := []HTTPieEnvironmentVariable{
+		{Name: "apiKey", Value: "staging-key", IsSecret: true},
+		{Name: "baseUrl", Value: "https://api.example.com"},
+		{Name: "password", Value: "hunter2", IsSecret: true},
+		{Name: "sort", Value: ""},
+		{Name: "token", Value: "prod-token", IsSecret: true},
+		{Name: "trace", Value: ""},
+	}
+	assertJSONEqual(t, "collection variables", got.Environments[0].Variables, wantVariables)
+}
+
+// TestPostmanRoundTripSampleExport starts from a real HTTPie export rather
+// than from the converter's own output shape.
+func TestPostmanRoundTripSampleExport(t *testing.T) {
+	data, err := os.ReadFile("sample-httpie-collection.json")
+	if err != nil {
+		t.Fatalf("reading sample: %v", err)
+	}
+	workspace, err := parseWorkspace(data, "sample-httpie-collection.json")
+	if err != nil {
+		t.Fatalf("parsing sample: %v", err)
+	}
+
+	got := requestsByName(postmanRoundTrip(t, workspace).Entry)
+	want := requestsByName(workspace.Entry)
+	if len(got) != len(want) {
+		t.Fatalf("got %d requests, want %d", len(got), len(want))
+	}
+
+	for name, wantReq := range want {
+		gotReq, ok := got[name]
+		if !ok {
+			t.Errorf("request %q missing after round trip", name)
+			continue
+		}
+		// Postman has no place for the auth target, so only compare the rest
+		assertJSONEqual(t, name+" auth type", gotReq.Auth.Type, wantReq.Auth.Type)
+		assertJSONEqual(t, name+" auth credentials", gotReq.Auth.Credentials, wantReq.Auth.Credentials)
+		assertJSONEqual(t, name+" body", gotReq.Body, wantReq.Body)
+		assertJSONEqual(t, name+" query params", gotReq.QueryParams, wantReq.QueryParams)
+		assertJSONEqual(t, name+" path params", gotReq.PathParams, wantReq.PathParams)
+	}
+
+	// "Create New User" opts out of its folder's basic auth
+	if auth := got["User Management/Create New User"].Auth; auth.Type != "none" {
+		t.Errorf("Create New User auth = %q, want none", auth.Type)
+	}
+}
+
+// TestPostmanRoundTripFileBody covers file bodies and path params in an
+// HTTPie-shaped request, whose auth inherits and whose Content-Type is set
+// explicitly.
+func TestPostmanRoundTripFileBody(t *testing.T) {
+	upload := HTTPieRequest{
+		Name:   "Upload",
+		URL:    "{{base_url}}/buckets/{bucket}/objects",
+		Method: "PUT",
+		Headers: []HTTPieHeader{
+			{Name: "Content-Type", Value: "image/png", Enabled: true},
+		},
+		QueryParams: []HTTPieQueryParam{},
+		PathParams:  []HTTPiePathParam{{Name: "bucket", Value: "avatars", Enabled: true}},
+		Auth:        HTTPieAuth{Type: "inherit"},
+		Body: HTTPieBody{
+			Type: "file",
+			File: HTTPieFile{Name: "images/me.png"},
+			Form: HTTPieForm{Fields: []HTTPieFormField{}},
+		},
+	}
+	workspace := HTTPieWorkspace{Entry: HTTPieEntry{
+		Name:     "Files",
+		Auth:     HTTPieAuth{Type: "bearer", Credentials: HTTPieAuthCredentials{Password: "{{token}}"}},
+		Requests: []HTTPieRequest{upload},
+	}}
+
+	got := postmanRoundTrip(t, workspace).Entry.Requests
+	if len(got) != 1 {
+		t.Fatalf("got %d requests, want 1", len(got))
+	}
+	assertJSONEqual(t, "url", got[0].URL, upload.URL)
+	assertJSONEqual(t, "headers", got[0].Headers, upload.Headers)
+	assertJSONEqual(t, "path params", got[0].PathParams, upload.PathParams)
+	assertJSONEqual(t, "body", got[0].Body, upload.Body)
+	if got[0].Auth.Type != "inherited" {
+		t.Errorf("auth = %q, want inherited", got[0].Auth.Type)
+	}
+}
+
+// postmanRoundTrip converts a workspace to a Postman collection, through
+// JSON, and back.
+func postmanRoundTrip(t *testing.T, workspace HTTPieWorkspace) HTTPieWorkspace {
+	t.Helper()
+	data, err := json.Marshal(convertWorkspaceToPostman(workspace, "workspace.json"))
+	if err != nil {
+		t.Fatalf("marshaling collection: %v", err)
+	}
+	collection, err := parsePostmanCollection(data)
+	if err != nil {
+		t.Fatalf("parsing collection: %v", err)
+	}
+	return convertPostmanToWorkspace(collection, nil)
+}
+
+// requestsByName indexes requests by their collection path and name.
+func requestsByName(entry HTTPieEntry) map[string]HTTPieRequest {
+	requests := map[string]HTTPieRequest{}
+	for _, req := range entry.Requests {
+		requests[req.Name] = req
+	}
+	var walk func(prefix string, collection HTTPieCollection)
+	walk = func(prefix string, collection HTTPieCollection) {
+		prefix += collection.Name + "/"
+		for _, req := range collection.Requests {
+			requests[prefix+req.Name] = req
+		}
+		for _, sub := range collection.Collections {
+			walk(prefix, sub)
+		}
+	}
+	for _, collection := range entry.Collections {
+		walk("", collection)
+	}
+	return requests
+}
+
+// assertJSONEqual compares values structurally and reports both as JSON.
+func assertJSONEqual(t *testing.T, what string, got, want any) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		gotJSON, _ := json.MarshalIndent(got, "", "  ")
+		wantJSON, _ := json.MarshalIndent(want, "", "  ")
+		t.Errorf("%s differs after round trip\ngot:\n%s\nwant:\n%s", what, gotJSON, wantJSON)
+	}
+}
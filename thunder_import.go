@@ -0,0 +1,340 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+)
+
+// Thunder Client collection export structure (thunder-collection_*.json).
+// Folders and requests are flat lists linked to their parent folder through
+// containerId; only the fields used for conversion are declared.
+type ThunderCollection struct {
+	Client         string           `json:"client"`
+	CollectionName string           `json:"collectionName"`
+	Folders        []ThunderFolder  `json:"folders"`
+	Requests       []ThunderRequest `json:"requests"`
+	Settings       ThunderSettings  `json:"settings"`
+}
+
+type ThunderFolder struct {
+	ID          string          `json:"_id"`
+	Name        string          `json:"name"`
+	ContainerID string          `json:"containerId"`
+	SortNum     float64         `json:"sortNum"`
+	Settings    ThunderSettings `json:"settings"`
+}
+
+// ThunderSettings holds the auth and headers a collection or folder passes
+// down to its requests.
+type ThunderSettings struct {
+	Auth    *ThunderAuth  `json:"auth"`
+	Headers []ThunderPair `json:"headers"`
+}
+
+type ThunderRequest struct {
+	ID          string        `json:"_id"`
+	ContainerID string        `json:"containerId"`
+	Name        string        `json:"name"`
+	URL         string        `json:"url"`
+	Method      string        `json:"method"`
+	SortNum     float64       `json:"sortNum"`
+	Headers     []ThunderPair `json:"headers"`
+	Params      []ThunderPair `json:"params"`
+	Body        *ThunderBody  `json:"body"`
+	Auth        *ThunderAuth  `json:"auth"`
+	Tests       []any         `json:"tests"`
+}
+
+type ThunderPair struct {
+	Name       string `json:"name"`
+	Value      string `json:"value"`
+	IsDisabled bool   `json:"isDisabled"`
+	IsPath     bool   `json:"isPath"` // Path parameter rather than query parameter
+}
+
+type ThunderBody struct {
+	Type    string          `json:"type"`
+	Raw     string          `json:"raw"`
+	Form    []ThunderPair   `json:"form"`
+	Files   []ThunderPair   `json:"files"` // Multipart file fields; the value is the path
+	GraphQL *ThunderGraphQL `json:"graphql"`
+	Binary  string          `json:"binary"`
+}
+
+type ThunderGraphQL struct {
+	Query     string `json:"query"`
+	Variables string `json:"variables"`
+}
+
+type ThunderAuth struct {
+	Type         string `json:"type"`
+	Bearer       string `json:"bearer"`
+	BearerPrefix string `json:"bearerPrefix"`
+	Basic        struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	} `json:"basic"`
+}
+
+// Thunder Client environment export structure (thunder-environment_*.json).
+type ThunderEnvironment struct {
+	Client          string                       `json:"client"`
+	EnvironmentName string                       `json:"environmentName"`
+	Data            []ThunderEnvironmentVariable `json:"data"`
+}
+
+type ThunderEnvironmentVariable struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	IsSecret bool   `json:"isSecret"`
+}
+
+// thunderRawFormats maps raw Thunder Client body types to MIME types.
+var thunderRawFormats = map[string]string{
+	"json": "application/json",
+	"xml":  "application/xml",
+	"text": "text/plain",
+}
+
+// parseThunderCollection parses a Thunder Client collection export. It
+// returns nil if the data is not one.
+func parseThunderCollection(data []byte) *ThunderCollection {
+	var collection ThunderCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return nil
+	}
+	if collection.Client != "Thunder Client" || collection.CollectionName == "" {
+		return nil
+	}
+	return &collection
+}
+
+// parseThunderEnvironment parses a Thunder Client environment export. It
+// returns nil if the data is not one.
+func parseThunderEnvironment(data []byte) *ThunderEnvironment {
+	var environment ThunderEnvironment
+	if err := json.Unmarshal(data, &environment); err != nil {
+		return nil
+	}
+	if environment.Client != "Thunder Client" || environment.EnvironmentName == "" {
+		return nil
+	}
+	return &environment
+}
+
+// loadThunderEnvironments reads the environment exports given with
+// -thunder-env. The first one becomes the default environment.
+func loadThunderEnvironments(paths string) ([]HTTPieEnvironment, error) {
+	var environments []HTTPieEnvironment
+	for _, path := range splitCommaList(paths) {
+		data, err := readInput(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading Thunder Client environment %s: %w", path, err)
+		}
+		export := parseThunderEnvironment(data)
+		if export == nil {
+			return nil, fmt.Errorf("%s is not a Thunder Client environment export", path)
+		}
+
+		environment := HTTPieEnvironment{
+			Name:      export.EnvironmentName,
+			IsDefault: len(environments) == 0,
+			Variables: []HTTPieEnvironmentVariable{},
+		}
+		for _, v := range export.Data {
+			if v.Name != "" {
+				environment.Variables = append(environment.Variables, HTTPieEnvironmentVariable{Name: v.Name, Value: v.Value, IsSecret: v.IsSecret})
+			}
+		}
+		environments = append(environments, environment)
+	}
+	return environments, nil
+}
+
+// thunderConverter indexes the folders and requests of a collection by
+// parent folder.
+type thunderConverter struct {
+	folders  map[string][]ThunderFolder
+	requests map[string][]ThunderRequest
+	warned   map[string]bool
+}
+
+// convertThunderToWorkspace converts a Thunder Client collection to an
+// HTTPie workspace, so it goes through the same conversion path as HTTPie
+// input. Folders become collections; headers set on the collection or a
+// folder are copied into each request below it.
+func convertThunderToWorkspace(collection *ThunderCollection, environments []HTTPieEnvironment) HTTPieWorkspace {
+	c := &thunderConverter{
+		folders:  map[string][]ThunderFolder{},
+		requests: map[string][]ThunderRequest{},
+		warned:   map[string]bool{},
+	}
+	for _, folder := range collection.Folders {
+		c.folders[folder.ContainerID] = append(c.folders[folder.ContainerID], folder)
+	}
+	for _, req := range collection.Requests {
+		c.requests[req.ContainerID] = append(c.requests[req.ContainerID], req)
+	}
+	for id := range c.folders {
+		folders := c.folders[id]
+		sort.SliceStable(folders, func(i, j int) bool { return folders[i].SortNum < folders[j].SortNum })
+	}
+	for id := range c.requests {
+		requests := c.requests[id]
+		sort.SliceStable(requests, func(i, j int) bool { return requests[i].SortNum < requests[j].SortNum })
+	}
+
+	root := c.convertFolder(ThunderFolder{Name: collection.CollectionName, Settings: collection.Settings}, nil)
+	return HTTPieWorkspace{
+		Meta: HTTPieMeta{Format: "httpie", Version: "1.0.0", Source: "thunder-client"},
+		Entry: HTTPieEntry{
+			Name:        root.Name,
+			Auth:        root.Auth,
+			Requests:    root.Requests,
+			Collections: root.Collections,
+		},
+		Environments: environments,
+	}
+}
+
+// convertFolder converts a folder and its children. headers are the
+// headers inherited from the enclosing folders.
+func (c *thunderConverter) convertFolder(folder ThunderFolder, headers []ThunderPair) HTTPieCollection {
+	collection := HTTPieCollection{
+		Name:     folder.Name,
+		Auth:     c.convertAuth(folder.Settings.Auth),
+		Requests: []HTTPieRequest{},
+	}
+	headers = append(append([]ThunderPair(nil), headers...), folder.Settings.Headers...)
+
+	for _, child := range c.folders[folder.ID] {
+		collection.Collections = append(collection.Collections, c.convertFolder(child, headers))
+	}
+	for _, req := range c.requests[folder.ID] {
+		collection.Requests = append(collection.Requests, c.convertRequest(req, headers))
+	}
+	return collection
+}
+
+func (c *thunderConverter) convertRequest(resource ThunderRequest, inheritedHeaders []ThunderPair) HTTPieRequest {
+	req := HTTPieRequest{
+		Name:        resource.Name,
+		URL:         resource.URL,
+		Method:      strings.ToUpper(resource.Method),
+		Headers:     []HTTPieHeader{},
+		QueryParams: []HTTPieQueryParam{},
+		PathParams:  []HTTPiePathParam{},
+		Auth:        c.convertAuth(resource.Auth),
+		Body:        c.convertBody(resource.Body),
+	}
+	if req.Method == "" {
+		req.Method = "GET"
+	}
+
+	// Inherited headers come first, unless the request sets the same name
+	for _, header := range inheritedHeaders {
+		overridden := false
+		for _, own := range resource.Headers {
+			if strings.EqualFold(own.Name, header.Name) {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			req.Headers = append(req.Headers, HTTPieHeader{Name: header.Name, Value: header.Value, Enabled: !header.IsDisabled})
+		}
+	}
+	for _, header := range resource.Headers {
+		req.Headers = append(req.Headers, HTTPieHeader{Name: header.Name, Value: header.Value, Enabled: !header.IsDisabled})
+	}
+
+	for _, param := range resource.Params {
+		if param.IsPath {
+			req.PathParams = append(req.PathParams, HTTPiePathParam{Name: param.Name, Value: param.Value, Enabled: !param.IsDisabled})
+			continue
+		}
+		req.QueryParams = append(req.QueryParams, HTTPieQueryParam{Name: param.Name, Value: param.Value, Enabled: !param.IsDisabled})
+	}
+
+	if len(resource.Tests) > 0 {
+		c.warn("tests", "Warning: Thunder Client tests are not supported and were dropped")
+	}
+	return req
+}
+
+// convertBody maps a Thunder Client body, which is typed by its type field.
+func (c *thunderConverter) convertBody(body *ThunderBody) HTTPieBody {
+	result := HTTPieBody{Type: "none", Form: HTTPieForm{Fields: []HTTPieFormField{}}}
+	if body == nil {
+		return result
+	}
+
+	switch body.Type {
+	case "", "none":
+	case "json", "xml", "text":
+		if body.Raw != "" {
+			result.Type = "text"
+			result.Text = HTTPieText{Value: body.Raw, Format: thunderRawFormats[body.Type]}
+		}
+	case "formencoded", "formdata":
+		result.Type = "form"
+		result.Form.IsMultipart = body.Type == "formdata"
+		for _, field := range body.Form {
+			result.Form.Fields = append(result.Form.Fields, HTTPieFormField{Name: field.Name, Value: field.Value, Enabled: !field.IsDisabled})
+		}
+		if body.Type == "formdata" {
+			for _, file := range body.Files {
+				result.Form.Fields = append(result.Form.Fields, HTTPieFormField{Name: file.Name, Value: file.Value, Enabled: !file.IsDisabled, Type: "file"})
+			}
+		}
+	case "graphql":
+		if body.GraphQL != nil {
+			result.Type = "graphql"
+			result.GraphQL = HTTPieGraphQL{Query: body.GraphQL.Query, Variables: body.GraphQL.Variables}
+		}
+	case "binary":
+		if body.Binary != "" {
+			result.Type = "file"
+			result.File.Name = body.Binary
+		}
+	default:
+		c.warn("body:"+body.Type, fmt.Sprintf("Warning: Thunder Client %q bodies are not supported and were dropped", body.Type))
+	}
+	return result
+}
+
+// convertAuth maps Thunder Client auth to HTTPie auth. A missing auth
+// inherits from the parent folder or collection.
+func (c *thunderConverter) convertAuth(auth *ThunderAuth) HTTPieAuth {
+	if auth == nil {
+		return HTTPieAuth{Type: "inherit"}
+	}
+
+	switch auth.Type {
+	case "", "inherit":
+		return HTTPieAuth{Type: "inherit"}
+	case "none":
+		return HTTPieAuth{Type: "none"}
+	case "basic":
+		return HTTPieAuth{Type: "basic", Credentials: HTTPieAuthCredentials{Username: auth.Basic.Username, Password: auth.Basic.Password}}
+	case "bearer":
+		if auth.BearerPrefix == "" || auth.BearerPrefix == "Bearer" {
+			return HTTPieAuth{Type: "bearer", Credentials: HTTPieAuthCredentials{Password: auth.Bearer}}
+		}
+		// HTTPie auth cannot express a custom prefix; send it as a header
+		return HTTPieAuth{Type: "apiKey", Credentials: HTTPieAuthCredentials{Username: "Authorization", Password: auth.BearerPrefix + " " + auth.Bearer}}
+	}
+
+	c.warn("auth:"+auth.Type, fmt.Sprintf("Warning: Thunder Client %q auth is not supported and was dropped", auth.Type))
+	return HTTPieAuth{Type: "none"}
+}
+
+func (c *thunderConverter) warn(key, message string) {
+	if !c.warned[key] {
+		c.warned[key] = true
+		log.Print(message)
+	}
+}